@@ -0,0 +1,13 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+//go:build go1.22
+
+package internal
+
+import "go/types"
+
+// Unalias returns the type an alias denotes, or the type itself. The
+// renderers resolve aliases, they have no counterpart in the layouts.
+func Unalias(t types.Type) types.Type {
+	return types.Unalias(t)
+}
@@ -0,0 +1,13 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+//go:build !go1.22
+
+package internal
+
+import "go/types"
+
+// Unalias returns the type itself, aliases are not materialized
+// by the type checker before Go 1.22.
+func Unalias(t types.Type) types.Type {
+	return t
+}
@@ -0,0 +1,113 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+)
+
+type (
+	// BuildContext is a build configuration of the inspected
+	// packages. Empty fields keep the setting of the go command.
+	BuildContext struct {
+		GOOS   string
+		GOARCH string
+		Tags   []string
+	}
+
+	// Divergence is a type declared differently in the build
+	// contexts of an inspection, or missing in some of them.
+	Divergence struct {
+		Name    string   // qualified Go type name
+		Missing []string // contexts without the type
+		Differs []string // contexts differing from the first one declaring the type
+	}
+)
+
+// String returns the settings of the context, e.g.
+// "GOOS=linux GOARCH=arm64 tags=a,b", or "default".
+func (c BuildContext) String() string {
+	parts := make([]string, 0, 3)
+	if c.GOOS != "" {
+		parts = append(parts, "GOOS="+c.GOOS)
+	}
+	if c.GOARCH != "" {
+		parts = append(parts, "GOARCH="+c.GOARCH)
+	}
+	if len(c.Tags) > 0 {
+		parts = append(parts, "tags="+strings.Join(c.Tags, ","))
+	}
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, " ")
+}
+
+// environ returns the environment of the go command for the context
+// with the additional KEY=VAL variables, nil for the default one.
+func (c BuildContext) environ(vars []string) []string {
+	if len(vars) == 0 && c.GOOS == "" && c.GOARCH == "" {
+		return nil
+	}
+	env := append(os.Environ(), vars...)
+	if c.GOOS != "" {
+		env = append(env, "GOOS="+c.GOOS)
+	}
+	if c.GOARCH != "" {
+		env = append(env, "GOARCH="+c.GOARCH)
+	}
+	return env
+}
+
+// buildFlags returns the flags of the go command for the context.
+func (c BuildContext) buildFlags() []string {
+	if len(c.Tags) == 0 {
+		return nil
+	}
+	return []string{"-tags=" + strings.Join(c.Tags, ",")}
+}
+
+// merge returns the union of the TypeMaps inspected in the contexts,
+// the type of the first context declaring a name is kept. Names not
+// declared identically in all contexts are reported as divergences.
+func merge(contexts []BuildContext, maps []TypeMap) (TypeMap, []Divergence) {
+	union := make(TypeMap)
+	for _, m := range maps {
+		for s, t := range m {
+			if _, ok := union[s]; !ok {
+				union[s] = t
+			}
+		}
+	}
+	if len(maps) < 2 {
+		return union, nil
+	}
+
+	divs := make([]Divergence, 0)
+	for s, t := range union {
+		d := Divergence{Name: s}
+		for i, m := range maps {
+			if u, ok := m[s]; !ok {
+				d.Missing = append(d.Missing, contexts[i].String())
+			} else if declaration(u) != declaration(t) {
+				d.Differs = append(d.Differs, contexts[i].String())
+			}
+		}
+		if len(d.Missing) > 0 || len(d.Differs) > 0 {
+			divs = append(divs, d)
+		}
+	}
+	sort.Slice(divs, func(i, j int) bool {
+		return divs[i].Name < divs[j].Name
+	})
+	return union, divs
+}
+
+// declaration returns the underlying type of a type as
+// string, comparable between independently loaded types.
+func declaration(t types.Type) string {
+	return types.TypeString(Unalias(t).Underlying(), nil)
+}
@@ -0,0 +1,64 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func TestBuildContext_String(t *testing.T) {
+	for ctx, want := range map[*BuildContext]string{
+		{}:                                     "default",
+		{GOOS: "linux"}:                        "GOOS=linux",
+		{GOARCH: "arm64", Tags: []string{"a"}}: "GOARCH=arm64 tags=a",
+		{"linux", "amd64", []string{"a", "b"}}: "GOOS=linux GOARCH=amd64 tags=a,b",
+	} {
+		if s := ctx.String(); s != want {
+			t.Errorf("unexpected %q", s)
+		}
+	}
+}
+
+func TestBuildContext_environ(t *testing.T) {
+	if env := (BuildContext{}).environ(nil); env != nil {
+		t.Errorf("unexpected %v", env)
+	}
+	env := BuildContext{GOOS: "linux"}.environ([]string{"GOOS=windows", "X=1"})
+	if n := len(env); n < 3 || !reflect.DeepEqual(env[n-3:], []string{"GOOS=windows", "X=1", "GOOS=linux"}) {
+		t.Errorf("unexpected %v", env)
+	}
+	if f := (BuildContext{Tags: []string{"a", "b"}}).buildFlags(); !reflect.DeepEqual(f, []string{"-tags=a,b"}) {
+		t.Errorf("unexpected %v", f)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	named := func(name string, u types.Type) types.Type {
+		obj := types.NewTypeName(0, types.NewPackage("p", "p"), name, nil)
+		return types.NewNamed(obj, u, nil)
+	}
+	a := BuildContext{GOOS: "a"}
+	b := BuildContext{GOOS: "b"}
+
+	t1 := named("T", types.Typ[types.Int32])
+	m1 := TypeMap{"p.T": t1, "p.U": named("U", types.Typ[types.String])}
+	m2 := TypeMap{"p.T": named("T", types.Typ[types.Int64]), "p.V": named("V", types.Typ[types.Bool])}
+	m2["p.U"] = named("U", types.Typ[types.String])
+
+	union, divs := merge([]BuildContext{a, b}, []TypeMap{m1, m2})
+	if len(union) != 3 || union["p.T"] != t1 {
+		t.Errorf("unexpected %v", union)
+	}
+	want := []Divergence{
+		{Name: "p.T", Differs: []string{"GOOS=b"}},
+		{Name: "p.V", Missing: []string{"GOOS=a"}},
+	}
+	if !reflect.DeepEqual(divs, want) {
+		t.Errorf("unexpected %v", divs)
+	}
+	if _, divs := merge([]BuildContext{a}, []TypeMap{m1}); divs != nil {
+		t.Errorf("unexpected %v", divs)
+	}
+}
@@ -0,0 +1,253 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/gcexportdata"
+	"golang.org/x/tools/go/packages"
+)
+
+type (
+	// Cache stores the types of inspected packages on disk, in the
+	// export data format of the go/types package. The entries are
+	// keyed by the package IDs and the content hashes of the files of
+	// the packages and of their dependencies, so any change to them
+	// leads to a new entry.
+	Cache struct {
+		Dir   string
+		Stats CacheStats
+	}
+
+	// CacheStats counts the packages found in a Cache (hits), those
+	// loaded by the go command instead (misses) and the entries stored.
+	CacheStats struct {
+		Hits, Misses, Stores int
+	}
+)
+
+// cacheVersion invalidates the entries written by previous versions.
+const cacheVersion = "typex-cache-1"
+
+// DefaultCacheDir returns the directory named by the TYPEXCACHE
+// environment variable, or the typex directory below the user
+// cache directory.
+func DefaultCacheDir() (string, error) {
+	if dir := os.Getenv("TYPEXCACHE"); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "typex"), nil
+}
+
+// String returns the statistics, e.g. "3 hits, 1 miss, 1 store".
+func (s CacheStats) String() string {
+	return count(s.Hits, "hit") + ", " + count(s.Misses, "miss") + ", " + count(s.Stores, "store")
+}
+
+// loadPackages loads the packages named by the patterns, from the
+// Cache if all of them have an entry, by the go command otherwise.
+// Queries, unexported types and test files bypass the Cache: queries
+// may resolve types of any package imported, which the export data of
+// a package does not carry, the export data omits unexported objects,
+// and the test variants of a package share its path.
+func (p *Packagist) loadPackages(ctx BuildContext, conf *packages.Config, patterns []string) ([]*packages.Package, error) {
+	if p.Cache == nil || p.TypeFilterFunc != nil || p.IncludeUnexported || p.IncludeTestFiles {
+		return p.PackageLoaderFunc(conf, patterns...)
+	}
+	roots, keys := p.cacheKeys(ctx, conf, patterns)
+	if pkgs, ok := p.readCache(roots, keys); ok {
+		p.Cache.Stats.Hits += len(pkgs)
+		return pkgs, nil
+	}
+
+	pkgs, err := p.PackageLoaderFunc(conf, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	p.Cache.Stats.Misses += len(pkgs)
+	for _, pkg := range pkgs {
+		if key, ok := keys[pkg.ID]; ok && len(pkg.Errors) == 0 && pkg.Types != nil {
+			if p.Cache.write(key, p.fset, pkg.Types) == nil {
+				p.Cache.Stats.Stores++
+			}
+		}
+	}
+	return pkgs, nil
+}
+
+// cacheKeys lists the packages named by the patterns without type
+// checking them, and returns them with their Cache keys by package ID.
+// No keys are returned when the packages can not be listed.
+func (p *Packagist) cacheKeys(ctx BuildContext, conf *packages.Config, patterns []string) ([]*packages.Package, map[string]string) {
+	mode := packages.NeedName
+	mode |= packages.NeedFiles
+	mode |= packages.NeedImports
+	mode |= packages.NeedDeps
+	mode |= packages.NeedModule
+	list := *conf
+	list.Mode = mode
+
+	roots, err := p.PackageLoaderFunc(&list, patterns...)
+	if err != nil {
+		return nil, nil
+	}
+	salt := cacheVersion + "\n" + runtime.Version() + "\n" + ctx.String() + "\n" + strings.Join(p.Env, "\n")
+	hashes := make(map[string]string)
+	failed := false
+
+	var hash func(pkg *packages.Package) string
+	hash = func(pkg *packages.Package) string {
+		if h, ok := hashes[pkg.ID]; ok {
+			return h
+		}
+		h := sha256.New()
+		_, _ = io.WriteString(h, salt+"\n"+pkg.ID+"\n")
+
+		files := append(append([]string{}, pkg.GoFiles...), pkg.OtherFiles...)
+		sort.Strings(files)
+		for _, name := range files {
+			sum, err := p.fileHash(name)
+			failed = failed || err != nil
+			_, _ = io.WriteString(h, name+" "+sum+"\n")
+		}
+		paths := make([]string, 0, len(pkg.Imports))
+		for path := range pkg.Imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			_, _ = io.WriteString(h, path+" "+hash(pkg.Imports[path])+"\n")
+		}
+		hashes[pkg.ID] = hex.EncodeToString(h.Sum(nil))
+		return hashes[pkg.ID]
+	}
+
+	keys := make(map[string]string, len(roots))
+	for _, pkg := range roots {
+		if len(pkg.Errors) > 0 {
+			return nil, nil
+		}
+		keys[pkg.ID] = hash(pkg)
+	}
+	if failed {
+		return nil, nil
+	}
+	return roots, keys
+}
+
+// fileHash returns the content hash of a file, or of its overlay.
+func (p *Packagist) fileHash(name string) (string, error) {
+	h := sha256.New()
+	if src, ok := p.Overlay[name]; ok {
+		_, _ = h.Write(src)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readCache returns the packages listed with their types read from
+// the Cache and their files parsed, if all of them have an entry.
+func (p *Packagist) readCache(roots []*packages.Package, keys map[string]string) ([]*packages.Package, bool) {
+	if len(roots) == 0 || len(keys) == 0 {
+		return nil, false
+	}
+	imports := make(map[string]*types.Package)
+	pkgs := make([]*packages.Package, 0, len(roots))
+
+	for _, root := range roots {
+		t, err := p.Cache.read(keys[root.ID], root.PkgPath, p.fset, imports)
+		if err != nil {
+			return nil, false
+		}
+		pkg := &packages.Package{
+			ID:      root.ID,
+			Name:    root.Name,
+			PkgPath: root.PkgPath,
+			GoFiles: root.GoFiles,
+			Module:  root.Module,
+			Fset:    p.fset,
+			Types:   t,
+		}
+		for _, name := range root.GoFiles {
+			var src interface{}
+			if b, ok := p.Overlay[name]; ok {
+				src = b
+			}
+			file, err := parser.ParseFile(p.fset, name, src, parser.ParseComments)
+			if err != nil {
+				return nil, false
+			}
+			pkg.Syntax = append(pkg.Syntax, file)
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, true
+}
+
+// path returns the file name of the entry of a key.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.Dir, key[:2], key+".typex")
+}
+
+// read returns the package of the entry of a key.
+func (c *Cache) read(key, path string, fset *token.FileSet, imports map[string]*types.Package) (*types.Package, error) {
+	f, err := os.Open(c.path(key))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return gcexportdata.Read(bufio.NewReader(f), fset, imports, path)
+}
+
+// write stores the package as entry of a key, with the positions of
+// the FileSet. The entry is written to a temporary file first, so
+// concurrent runs never read partial entries.
+func (c *Cache) write(key string, fset *token.FileSet, pkg *types.Package) error {
+	name := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), "tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	w := bufio.NewWriter(f)
+	if err = gcexportdata.Write(w, fset, pkg); err == nil {
+		err = w.Flush()
+	}
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
@@ -0,0 +1,65 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	gotypes "go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func TestPackagist_Cache(t *testing.T) {
+	pkgPath := reflect.TypeOf(p5.Order{}).PkgPath()
+	cache := &Cache{Dir: t.TempDir()}
+
+	inspect := func() (*Packagist, TypeMap) {
+		pac := &Packagist{
+			PathFilterFunc: CreatePathFilterFunc([]string{`p5\.Order$`}, nil),
+			Cache:          cache,
+		}
+		types, err := pac.Inspect(pkgPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pac, types
+	}
+
+	_, want := inspect()
+	pac, types := inspect()
+	if cache.Stats != (CacheStats{Hits: 1, Misses: 1, Stores: 1}) {
+		t.Errorf("unexpected %v", cache.Stats)
+	}
+	for s, u := range want {
+		if v := types[s]; v == nil || declaration(u) != declaration(v) {
+			t.Errorf("unexpected %s", s)
+		}
+	}
+	named := types[pkgPath+".Order"].(*gotypes.Named)
+	if pos := pac.Position(named); pos != "internal/testdata/p5/types.go:16" {
+		t.Errorf("unexpected %s", pos)
+	}
+	if doc := pac.Doc(named.Underlying().(*gotypes.Struct).Field(0)); doc != "ID is the order number." {
+		t.Errorf("unexpected %q", doc)
+	}
+
+	// a corrupt entry is a miss
+	entries, _ := filepath.Glob(filepath.Join(cache.Dir, "*", "*.typex"))
+	for _, name := range entries {
+		if err := os.WriteFile(name, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inspect(); cache.Stats != (CacheStats{Hits: 1, Misses: 2, Stores: 2}) {
+		t.Errorf("unexpected %v", cache.Stats)
+	}
+}
+
+func TestCacheStats_String(t *testing.T) {
+	if s := (CacheStats{Hits: 1, Misses: 2}).String(); s != "1 hit, 2 misses, 0 stores" {
+		t.Errorf("unexpected %q", s)
+	}
+}
@@ -15,23 +15,10 @@ func CreatePathFilterFunc(include, exclude []string) PathFilterFunc {
 	excluded := make([]*regexp.Regexp, 0)
 
 	for _, expr := range include {
-		re0, err := regexp.Compile(expr)
-		if err != nil {
-			esc := regexp.QuoteMeta(expr)
-			re1, _ := regexp.Compile(esc)
-			re0 = re1
-		}
-		included = append(included, re0)
+		included = append(included, compilePattern(expr))
 	}
-
 	for _, expr := range exclude {
-		re0, err := regexp.Compile(expr)
-		if err != nil {
-			esc := regexp.QuoteMeta(expr)
-			re1, _ := regexp.Compile(esc)
-			re0 = re1
-		}
-		excluded = append(excluded, re0)
+		excluded = append(excluded, compilePattern(expr))
 	}
 
 	return func(name string) bool {
@@ -73,13 +60,7 @@ func CreatePathReplaceFunc(list []string) PathReplaceFunc {
 		if p0 == "" {
 			continue
 		}
-		re0, err := regexp.Compile(p0)
-		if err != nil {
-			esc := regexp.QuoteMeta(p0)
-			re1, _ := regexp.Compile(esc)
-			re0 = re1
-		}
-		pairs = append(pairs, replace{re0, p1})
+		pairs = append(pairs, replace{compilePattern(p0), p1})
 	}
 
 	re1 := regexp.MustCompile(`\./+`)
@@ -87,7 +68,13 @@ func CreatePathReplaceFunc(list []string) PathReplaceFunc {
 	re3 := regexp.MustCompile(`/+`)
 	re4 := regexp.MustCompile(`\.+`)
 
-	return func(s string) string {
+	var f PathReplaceFunc
+	f = func(s string) string {
+		if i := strings.IndexByte(s, '['); i > -1 {
+			// instantiated generic type, the type arguments are replaced one
+			// by one, e.g. "p6.Pair[p6.Color, *p6.Node]"
+			return f(s[:i]) + qualified.ReplaceAllStringFunc(s[i:], f)
+		}
 		for i := 0; i < len(pairs); i++ {
 			s = pairs[i].old.ReplaceAllString(s, pairs[i].new)
 		}
@@ -102,4 +89,18 @@ func CreatePathReplaceFunc(list []string) PathReplaceFunc {
 
 		return s
 	}
+	return f
+}
+
+// qualified matches the qualified type names in type arguments.
+var qualified = regexp.MustCompile(`[^\s\[\],*]+\.[^\s\[\],*]+`)
+
+// compilePattern compiles a regular expression, falling back
+// to a literal match when the expression is not valid.
+func compilePattern(expr string) *regexp.Regexp {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		re = regexp.MustCompile(regexp.QuoteMeta(expr))
+	}
+	return re
 }
@@ -0,0 +1,109 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Collision is a set of types mapped onto the same
+	// qualified name by a PathReplaceFunc.
+	Collision struct {
+		Name  string   // replaced qualified name
+		Types []string // qualified Go type names in order
+	}
+
+	// Collisions is a list of collisions, it implements the
+	// error interface reporting the colliding types.
+	Collisions []Collision
+)
+
+// FindCollisions returns the collisions of the types of a TypeMap
+// after the path replacement f, ordered by the replaced name. Layouts
+// declaring all types in a single namespace, e.g. GraphQL, pass flat
+// reporting the types they declare, these collide by their names
+// without package path, others are left out.
+func FindCollisions(m TypeMap, f PathReplaceFunc, flat func(types.Type) bool) Collisions {
+	names := Names{PathReplaceFunc: f}
+	byName := make(map[string][]string)
+	for s, t := range m {
+		if flat != nil && !flat(t) {
+			continue
+		}
+		n := collisionKey(names, s, flat != nil)
+		byName[n] = append(byName[n], s)
+	}
+
+	list := make(Collisions, 0)
+	for n, types := range byName {
+		if len(types) > 1 {
+			sort.Strings(types)
+			list = append(list, Collision{Name: n, Types: types})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func collisionKey(names Names, s string, flat bool) string {
+	if flat {
+		_, name := names.Split(s)
+		return name
+	}
+	return names.Replace(s)
+}
+
+// Error returns a report of the collisions, one per line.
+func (c Collisions) Error() string {
+	b := strings.Builder{}
+	b.WriteString("name collision of distinct types")
+	for _, col := range c {
+		b.WriteString("\n    " + col.Name + " <- " + strings.Join(col.Types, ", "))
+	}
+	return b.String()
+}
+
+// DisambiguateFunc returns a PathReplaceFunc resolving the collisions
+// of the path replacement f for the types of a TypeMap, see flat of
+// FindCollisions. The first of the colliding types in order of their
+// Go names keeps its name, the others get a numeric suffix not taken
+// by any other type, e.g. "T_2".
+func DisambiguateFunc(m TypeMap, f PathReplaceFunc, flat func(types.Type) bool) PathReplaceFunc {
+	names := Names{PathReplaceFunc: f}
+	taken := make(map[string]bool, len(m))
+	for s, t := range m {
+		if flat == nil || flat(t) {
+			taken[collisionKey(names, s, flat != nil)] = true
+		}
+	}
+
+	renamed := make(map[string]string)
+	for _, col := range FindCollisions(m, f, flat) {
+		for i, n := 1, 2; i < len(col.Types); n++ {
+			s := col.Name + "_" + strconv.Itoa(n)
+			if taken[s] {
+				continue
+			}
+			taken[s] = true
+			if path, _ := names.Split(col.Types[i]); flat != nil && path != "" {
+				renamed[col.Types[i]] = path + "." + s
+			} else {
+				renamed[col.Types[i]] = s
+			}
+			i++
+		}
+	}
+
+	return func(s string) string {
+		if r, ok := renamed[s]; ok {
+			return r
+		}
+		return names.Replace(s)
+	}
+}
@@ -0,0 +1,72 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func TestFindCollisions(t *testing.T) {
+	m := TypeMap{"a/v1.T": nil, "a/v2.T": nil, "a/v2.U": nil, "a/v2.T_2": nil}
+	f := CreatePathReplaceFunc([]string{"/v1:", "/v2:"})
+
+	c := FindCollisions(m, f, nil)
+	want := Collisions{{Name: "a.T", Types: []string{"a/v1.T", "a/v2.T"}}}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("unexpected %v", c)
+	}
+	if s := c.Error(); s != "name collision of distinct types\n    a.T <- a/v1.T, a/v2.T" {
+		t.Errorf("unexpected %q", s)
+	}
+	if c := FindCollisions(m, nil, nil); len(c) != 0 {
+		t.Errorf("unexpected %v", c)
+	}
+}
+
+func TestDisambiguateFunc(t *testing.T) {
+	m := TypeMap{"a/v1.T": nil, "a/v2.T": nil, "a/v3.T": nil, "a/v2.T_2": nil}
+	f := DisambiguateFunc(m, CreatePathReplaceFunc([]string{"/v[123]:"}), nil)
+
+	for s, want := range map[string]string{
+		"a/v1.T":   "a.T",
+		"a/v2.T":   "a.T_3",
+		"a/v3.T":   "a.T_4",
+		"a/v2.T_2": "a.T_2",
+		"a/v1":     "a",
+	} {
+		if got := f(s); got != want {
+			t.Errorf("unexpected %s: %s", s, got)
+		}
+	}
+	if c := FindCollisions(m, f, nil); len(c) != 0 {
+		t.Errorf("unexpected %v", c)
+	}
+}
+
+func TestDisambiguateFunc_Flat(t *testing.T) {
+	m := TypeMap{"a/p1.T": nil, "a/p2.T": nil, "a/p2.U": nil, "a/p3.T_2": nil, "a/p3.U": types.Typ[types.Int]}
+	flat := func(t types.Type) bool { return t == nil } // a/p3.U not declared
+
+	c := FindCollisions(m, nil, flat)
+	want := Collisions{{Name: "T", Types: []string{"a/p1.T", "a/p2.T"}}}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("unexpected %v", c)
+	}
+
+	f := DisambiguateFunc(m, nil, flat)
+	for s, want := range map[string]string{
+		"a/p1.T":   "a/p1.T",
+		"a/p2.T":   "a/p2.T_3",
+		"a/p2.U":   "a/p2.U",
+		"a/p3.T_2": "a/p3.T_2",
+	} {
+		if got := f(s); got != want {
+			t.Errorf("unexpected %s: %s", s, got)
+		}
+	}
+	if c := FindCollisions(m, f, flat); len(c) != 0 {
+		t.Errorf("unexpected %v", c)
+	}
+}
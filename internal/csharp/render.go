@@ -0,0 +1,306 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package csharp
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
+)
+
+type (
+	// TypeRender renders Go types as C# records serialized with
+	// System.Text.Json, one file per namespace.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+		TagKey            string
+		Ranking           typex.Ranking
+		SortFields        bool
+	}
+
+	file struct {
+		namespace string
+		usings    map[string]bool
+	}
+)
+
+// Render converts a TypeMap to C# files. Structs are rendered as
+// positional records, anonymous structs as records named after the
+// enclosing record and the field, enumerations as enums. Aliases have no counterpart,
+// references to them resolve to the aliased type. Nullable fields
+// tagged "omitempty" are omitted when null.
+func (r *TypeRender) Render(m typex.TypeMap) []typex.File {
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		Ranking:           r.Ranking,
+		SortFields:        r.SortFields,
+	}
+	mod := b.Build(m)
+	files := make([]typex.File, 0)
+
+	for _, p := range mod.Packages() {
+		f := &file{namespace: namespace(p), usings: make(map[string]bool)}
+		decls := make([]string, 0)
+
+		for _, d := range mod.Package(p) {
+			switch d.Kind {
+			case model.Struct:
+				decls = append(decls, r.records(f, d)...)
+			case model.Enum:
+				decls = append(decls, r.enum(f, d))
+			case model.Alias:
+				for _, n := range d.Nested {
+					decls = append(decls, r.records(f, n)...)
+				}
+			}
+		}
+		if len(decls) == 0 {
+			continue
+		}
+		path := strings.ReplaceAll(strings.ReplaceAll(f.namespace, "@", ""), ".", "/")
+		if path != "" {
+			path += "/"
+		}
+		files = append(files, typex.File{Path: path + "Types.cs", Content: r.source(f, decls)})
+	}
+	return files
+}
+
+func (r *TypeRender) source(f *file, decls []string) string {
+	buf := bytes.Buffer{}
+	r.write(&buf, "#nullable enable\n\n")
+
+	usings := make([]string, 0, len(f.usings))
+	for u := range f.usings {
+		usings = append(usings, u)
+	}
+	sort.Strings(usings)
+	for _, u := range usings {
+		r.write(&buf, "using %s;\n", u)
+	}
+	if len(usings) > 0 {
+		r.write(&buf, "\n")
+	}
+	if f.namespace != "" {
+		r.write(&buf, "namespace %s;\n\n", f.namespace)
+	}
+	r.write(&buf, "%s", strings.Join(decls, "\n"))
+	return buf.String()
+}
+
+// records returns the declaration of a record followed by the
+// declarations of its anonymous structs.
+func (r *TypeRender) records(f *file, d *model.Decl) []string {
+	buf := bytes.Buffer{}
+	name := identifier(d.Flat())
+	r.write(&buf, "public sealed record %s(", name)
+
+	for i, fld := range d.Fields {
+		attrs := "JsonPropertyName(" + strconv.Quote(fld.Wire) + ")"
+		typ := r.typeOf(f, d, fld.Type)
+
+		if fld.Optional {
+			attrs += ", JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)"
+			typ = nullable(typ)
+		}
+		prop := identifier(fld.Name)
+		if prop == name {
+			prop += "_" // members cannot be named like the enclosing type
+		}
+		f.usings["System.Text.Json.Serialization"] = true
+		r.write(&buf, "\n    [property: %s] %s %s", attrs, typ, prop)
+
+		if i < len(d.Fields)-1 {
+			r.write(&buf, ",")
+		} else {
+			r.write(&buf, "\n")
+		}
+	}
+	r.write(&buf, ");\n")
+
+	decls := []string{buf.String()}
+	for _, n := range d.Nested {
+		decls = append(decls, r.records(f, n)...)
+	}
+	return decls
+}
+
+func (r *TypeRender) enum(f *file, d *model.Decl) string {
+	buf := bytes.Buffer{}
+	seen := make(map[string]bool)
+
+	if d.Type.Kind == model.String {
+		f.usings["System.Text.Json.Serialization"] = true
+		r.write(&buf, "[JsonConverter(typeof(JsonStringEnumConverter<%s>))]\n", identifier(d.Name))
+		r.write(&buf, "public enum %s\n{\n", identifier(d.Name))
+
+		for _, v := range d.Values {
+			n, s := identifier(pascal(v.Name)), constant.StringVal(v.Value)
+			if seen[n] || seen[s] {
+				continue // System.Text.Json rejects duplicate member names
+			}
+			seen[n], seen[s] = true, true
+			r.write(&buf, "    [JsonStringEnumMemberName(%s)]\n    %s,\n", strconv.Quote(s), n)
+		}
+		r.write(&buf, "}\n")
+		return buf.String()
+	}
+
+	r.write(&buf, "public enum %s : %s\n{\n", identifier(d.Name), basic(d.Type))
+	for _, v := range d.Values {
+		n := identifier(pascal(v.Name))
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		r.write(&buf, "    %s = %s,\n", n, v.Value.ExactString())
+	}
+	r.write(&buf, "}\n")
+	return buf.String()
+}
+
+// typeOf returns the C# type of a type reference. Types without
+// serializable counterpart are rendered as JSON elements.
+func (r *TypeRender) typeOf(f *file, ctx *model.Decl, t *model.Type) string {
+	t = model.Unalias(t, false)
+	typ := basic(t)
+
+	switch t.Kind {
+	case model.Time:
+		f.usings["System"] = true
+		typ = "DateTimeOffset"
+	case model.List:
+		f.usings["System.Collections.Generic"] = true
+		typ = "List<" + r.typeOf(f, ctx, t.Elem) + ">"
+	case model.Map:
+		f.usings["System.Collections.Generic"] = true
+		typ = "Dictionary<" + keyOf(t.Key) + ", " + r.typeOf(f, ctx, t.Elem) + ">"
+	case model.Ref:
+		typ = r.reference(ctx, t.Decl)
+	case model.Any:
+		f.usings["System.Text.Json"] = true
+		typ = "JsonElement"
+	}
+	if t.Nullable {
+		return nullable(typ)
+	}
+	return typ
+}
+
+// keyOf returns the type of a dictionary key, enumerations are
+// keyed by their values, like encoding/json does.
+func keyOf(t *model.Type) string {
+	t = model.Unalias(t, true)
+	if t.Kind == model.Int && !t.Nullable || t.Kind == model.Uint && !t.Nullable {
+		return basic(t)
+	}
+	return "string"
+}
+
+func basic(t *model.Type) string {
+	switch t.Kind {
+	case model.Bool:
+		return "bool"
+	case model.String:
+		return "string"
+	case model.Bytes:
+		return "byte[]" // base64 encoded, like encoding/json
+	case model.Float:
+		if t.Bits == 32 {
+			return "float"
+		}
+		return "double"
+	case model.Int:
+		return map[int]string{8: "sbyte", 16: "short", 32: "int", 64: "long"}[t.Bits]
+	case model.Uint:
+		return map[int]string{8: "byte", 16: "ushort", 32: "uint", 64: "ulong"}[t.Bits]
+	}
+	return ""
+}
+
+// reference returns the name of a declaration, qualified by its
+// namespace when declared in another namespace.
+func (r *TypeRender) reference(ctx *model.Decl, d *model.Decl) string {
+	if d.Path == ctx.Path || d.Path == "" {
+		return identifier(d.Flat())
+	}
+	return "global::" + namespace(d.Path) + "." + identifier(d.Flat())
+}
+
+func (TypeRender) write(w *bytes.Buffer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
+
+func nullable(typ string) string {
+	if strings.HasSuffix(typ, "?") {
+		return typ
+	}
+	return typ + "?"
+}
+
+// namespace returns the namespace of a path consisting of valid
+// identifiers.
+func namespace(path string) string {
+	parts := make([]string, 0)
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			parts = append(parts, identifier(strings.Map(func(c rune) rune {
+				if c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+					return c
+				}
+				return '_'
+			}, s)))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func pascal(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// identifier returns a valid C# identifier, keywords are escaped
+// with an "@" prefix.
+func identifier(s string) string {
+	switch s {
+	case "abstract", "as", "base", "bool", "break", "byte", "case", "catch",
+		"char", "checked", "class", "const", "continue", "decimal", "default",
+		"delegate", "do", "double", "else", "enum", "event", "explicit",
+		"extern", "false", "finally", "fixed", "float", "for", "foreach",
+		"goto", "if", "implicit", "in", "int", "interface", "internal", "is",
+		"lock", "long", "namespace", "new", "null", "object", "operator",
+		"out", "override", "params", "private", "protected", "public",
+		"readonly", "ref", "return", "sbyte", "sealed", "short", "sizeof",
+		"stackalloc", "static", "string", "struct", "switch", "this", "throw",
+		"true", "try", "typeof", "uint", "ulong", "unchecked", "unsafe",
+		"ushort", "using", "virtual", "void", "volatile", "while":
+		return "@" + s
+	}
+	if s != "" && !unicode.IsLetter([]rune(s)[0]) && s[0] != '_' {
+		return "_" + s
+	}
+	return s
+}
+
+// Reserved reports whether a type name or a package path segment
+// is a keyword or clashes with a name the rendered C# code relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "DateTimeOffset", "Dictionary", "JsonConverter", "JsonElement",
+		"JsonIgnore", "JsonIgnoreCondition", "JsonPropertyName",
+		"JsonStringEnumConverter", "JsonStringEnumMemberName", "List",
+		"Object", "String", "System", "global":
+		return true
+	}
+	return identifier(s) != s
+}
@@ -0,0 +1,67 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package csharp
+
+import (
+	"reflect"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func TestTypeRender_Render(t *testing.T) {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p5\.`}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(p5.Order{}).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+	tr := TypeRender{PathReplaceFunc: typex.CreatePathReplaceFunc([]string{".*/testdata:"})}
+
+	want := `#nullable enable
+
+using System;
+using System.Collections.Generic;
+using System.Text.Json.Serialization;
+
+namespace p5;
+
+public sealed record Item(
+    [property: JsonPropertyName("sku")] string SKU,
+    [property: JsonPropertyName("quantity")] int Quantity
+);
+
+public enum Level : byte
+{
+    Low = 0,
+    High = 1,
+    Max = 1,
+}
+
+public sealed record Order(
+    [property: JsonPropertyName("id")] string ID,
+    [property: JsonPropertyName("status")] Status Status,
+    [property: JsonPropertyName("level"), JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)] Level? Level,
+    [property: JsonPropertyName("items")] List<Item> Items,
+    [property: JsonPropertyName("labels")] Dictionary<string, string> Labels,
+    [property: JsonPropertyName("created")] DateTimeOffset Created,
+    [property: JsonPropertyName("note"), JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)] string? Note,
+    [property: JsonPropertyName("ref")] global::p2.S? Ref
+);
+
+public enum Status : long
+{
+    Open = 1,
+    Closed = 2,
+}
+`
+	files := tr.Render(types)
+	if len(files) != 2 || files[1].Path != "p5/Types.cs" {
+		t.Fatal("unexpected")
+	}
+	if files[1].Content != want {
+		t.Errorf("unexpected\n%s", files[1].Content)
+	}
+}
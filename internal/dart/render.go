@@ -0,0 +1,306 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package dart
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"sort"
+	"strings"
+	"unicode"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
+)
+
+type (
+	// TypeRender renders Go types as Dart classes annotated for
+	// json_serializable, one library per package.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+		TagKey            string
+		Ranking           typex.Ranking
+		SortFields        bool
+	}
+
+	library struct {
+		path    string
+		imports map[string]string
+	}
+)
+
+// Render converts a TypeMap to Dart libraries. Structs are rendered
+// as immutable classes, anonymous structs as classes named after the
+// enclosing class and the field, enumerations as enhanced enums
+// carrying their value. Aliases have no counterpart, references to
+// them resolve to the aliased type. Nullable fields tagged
+// "omitempty" are omitted when null.
+func (r *TypeRender) Render(m typex.TypeMap) []typex.File {
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		Ranking:           r.Ranking,
+		SortFields:        r.SortFields,
+	}
+	mod := b.Build(m)
+	files := make([]typex.File, 0)
+
+	for _, p := range mod.Packages() {
+		lib := &library{path: libPath(p), imports: make(map[string]string)}
+		decls := make([]string, 0)
+
+		for _, d := range mod.Package(p) {
+			switch d.Kind {
+			case model.Struct:
+				decls = append(decls, r.classes(lib, d)...)
+			case model.Enum:
+				decls = append(decls, r.enum(d))
+			case model.Alias:
+				for _, n := range d.Nested {
+					decls = append(decls, r.classes(lib, n)...)
+				}
+			}
+		}
+		if len(decls) > 0 {
+			files = append(files, typex.File{Path: lib.path, Content: r.source(lib, decls)})
+		}
+	}
+	return files
+}
+
+func (r *TypeRender) source(lib *library, decls []string) string {
+	buf := bytes.Buffer{}
+	r.write(&buf, "import 'package:json_annotation/json_annotation.dart';\n\n")
+
+	uris := make([]string, 0, len(lib.imports))
+	for u := range lib.imports {
+		uris = append(uris, u)
+	}
+	sort.Strings(uris)
+	for _, u := range uris {
+		r.write(&buf, "import %s as %s;\n", quote(u), lib.imports[u])
+	}
+	if len(uris) > 0 {
+		r.write(&buf, "\n")
+	}
+
+	name := lib.path[strings.LastIndex(lib.path, "/")+1:]
+	r.write(&buf, "part %s;\n\n", quote(strings.TrimSuffix(name, ".dart")+".g.dart"))
+	r.write(&buf, "%s", strings.Join(decls, "\n"))
+	return buf.String()
+}
+
+// classes returns the declaration of a class followed by the
+// declarations of its anonymous structs.
+func (r *TypeRender) classes(lib *library, d *model.Decl) []string {
+	name := identifier(d.Flat())
+	buf := bytes.Buffer{}
+
+	r.write(&buf, "@JsonSerializable(explicitToJson: true)\nclass %s {\n", name)
+	if len(d.Fields) == 0 {
+		r.write(&buf, "  const %s();\n\n", name)
+	} else {
+		r.write(&buf, "  const %s({\n", name)
+		for _, fld := range d.Fields {
+			if fld.Nullable() {
+				r.write(&buf, "    this.%s,\n", r.field(fld))
+			} else {
+				r.write(&buf, "    required this.%s,\n", r.field(fld))
+			}
+		}
+		r.write(&buf, "  });\n\n")
+	}
+	r.write(&buf, "  factory %s.fromJson(Map<String, dynamic> json) => _$%sFromJson(json);\n\n", name, name)
+
+	for _, fld := range d.Fields {
+		keys := make([]string, 0)
+		if n := r.field(fld); n != fld.Wire {
+			keys = append(keys, "name: "+quote(fld.Wire))
+		}
+		if fld.Optional {
+			keys = append(keys, "includeIfNull: false")
+		}
+		if len(keys) > 0 {
+			r.write(&buf, "  @JsonKey(%s)\n", strings.Join(keys, ", "))
+		}
+		typ := r.typeOf(lib, d, fld.Type)
+		if fld.Optional {
+			typ = nullable(typ)
+		}
+		r.write(&buf, "  final %s %s;\n", typ, r.field(fld))
+	}
+	if len(d.Fields) > 0 {
+		r.write(&buf, "\n")
+	}
+	r.write(&buf, "  Map<String, dynamic> toJson() => _$%sToJson(this);\n}\n", name)
+
+	decls := []string{buf.String()}
+	for _, n := range d.Nested {
+		decls = append(decls, r.classes(lib, n)...)
+	}
+	return decls
+}
+
+func (r *TypeRender) field(f *model.Field) string {
+	return identifier(typex.LowerCamelCase(f.Name))
+}
+
+func (r *TypeRender) enum(d *model.Decl) string {
+	name := identifier(d.Name)
+	typ := "int"
+	if d.Type.Kind == model.String {
+		typ = "String"
+	}
+
+	values := make([]string, 0, len(d.Values))
+	seen := make(map[string]bool)
+	for _, v := range d.Values {
+		n := identifier(typex.LowerCamelCase(v.Name))
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+
+		val := v.Value.ExactString()
+		if v.Value.Kind() == constant.String {
+			val = quote(constant.StringVal(v.Value))
+		}
+		values = append(values, "  "+n+"("+val+")")
+	}
+
+	buf := bytes.Buffer{}
+	r.write(&buf, "@JsonEnum(valueField: 'value')\nenum %s {\n%s;\n\n", name, strings.Join(values, ",\n"))
+	r.write(&buf, "  const %s(this.value);\n\n  final %s value;\n}\n", name, typ)
+	return buf.String()
+}
+
+// typeOf returns the Dart type of a type reference. Types without
+// serializable counterpart are rendered as nullable objects.
+func (r *TypeRender) typeOf(lib *library, ctx *model.Decl, t *model.Type) string {
+	t = model.Unalias(t, false)
+	typ := "Object?"
+
+	switch t.Kind {
+	case model.Bool:
+		typ = "bool"
+	case model.Int, model.Uint:
+		typ = "int"
+	case model.Float:
+		typ = "double"
+	case model.String, model.Bytes:
+		typ = "String" // []byte is base64 encoded, like encoding/json
+	case model.Time:
+		typ = "DateTime"
+	case model.List:
+		typ = "List<" + r.typeOf(lib, ctx, t.Elem) + ">"
+	case model.Map:
+		typ = "Map<" + keyOf(t.Key) + ", " + r.typeOf(lib, ctx, t.Elem) + ">"
+	case model.Ref:
+		typ = r.reference(lib, t.Decl)
+	}
+	if t.Nullable {
+		return nullable(typ)
+	}
+	return typ
+}
+
+// keyOf returns the type of a map key, enumerations are keyed by
+// their values, like encoding/json does.
+func keyOf(t *model.Type) string {
+	t = model.Unalias(t, true)
+	if t.Kind == model.Int && !t.Nullable || t.Kind == model.Uint && !t.Nullable {
+		return "int"
+	}
+	return "String"
+}
+
+// reference returns the name of a declaration, prefixed by the
+// import prefix of its library when declared in another library.
+func (r *TypeRender) reference(lib *library, d *model.Decl) string {
+	name := identifier(d.Flat())
+	path := libPath(d.Path)
+	if path == lib.path {
+		return name
+	}
+	from := strings.Split(lib.path, "/")
+	to := strings.Split(path, "/")
+
+	i := 0
+	for i < len(from)-1 && i < len(to)-1 && from[i] == to[i] {
+		i++
+	}
+	uri := strings.Repeat("../", len(from)-1-i) + strings.Join(to[i:], "/")
+
+	prefix := strings.Join(to[:len(to)-1], "_")
+	if prefix == "" {
+		prefix = "types"
+	}
+	lib.imports[uri] = prefix
+	return prefix + "." + name
+}
+
+func (TypeRender) write(w *bytes.Buffer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
+
+func nullable(typ string) string {
+	if strings.HasSuffix(typ, "?") {
+		return typ
+	}
+	return typ + "?"
+}
+
+// libPath returns the file path of the library of a package path,
+// consisting of valid, lower case identifiers.
+func libPath(path string) string {
+	parts := make([]string, 0)
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			parts = append(parts, identifier(strings.Map(func(c rune) rune {
+				if c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+					return unicode.ToLower(c)
+				}
+				return '_'
+			}, s)))
+		}
+	}
+	return strings.Join(append(parts, "types.dart"), "/")
+}
+
+// identifier returns a valid Dart identifier, reserved words get
+// an underscore suffix.
+func identifier(s string) string {
+	switch s {
+	case "assert", "break", "case", "catch", "class", "const", "continue",
+		"default", "do", "else", "enum", "extends", "false", "final",
+		"finally", "for", "if", "in", "is", "new", "null", "rethrow",
+		"return", "super", "switch", "this", "throw", "true", "try", "var",
+		"void", "while", "with":
+		return s + "_"
+	}
+	if s != "" && !unicode.IsLetter([]rune(s)[0]) && s[0] != '_' && s[0] != '$' {
+		return "v" + s
+	}
+	return s
+}
+
+// quote returns a single quoted Dart string literal.
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `$`, `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + r.Replace(s) + "'"
+}
+
+// Reserved reports whether a type name or a package path segment
+// is a keyword or clashes with a name the rendered Dart code relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "DateTime", "JsonEnum", "JsonKey", "JsonSerializable", "List",
+		"Map", "Never", "Null", "Object", "String", "bool", "double",
+		"dynamic", "int", "num":
+		return true
+	}
+	return identifier(s) != s
+}
@@ -0,0 +1,107 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package dart
+
+import (
+	"reflect"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func TestTypeRender_Render(t *testing.T) {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p5\.`}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(p5.Order{}).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+	tr := TypeRender{PathReplaceFunc: typex.CreatePathReplaceFunc([]string{".*/testdata:"})}
+
+	want := `import 'package:json_annotation/json_annotation.dart';
+
+import '../p2/types.dart' as p2;
+
+part 'types.g.dart';
+
+@JsonSerializable(explicitToJson: true)
+class Item {
+  const Item({
+    required this.sku,
+    required this.quantity,
+  });
+
+  factory Item.fromJson(Map<String, dynamic> json) => _$ItemFromJson(json);
+
+  final String sku;
+  final int quantity;
+
+  Map<String, dynamic> toJson() => _$ItemToJson(this);
+}
+
+@JsonEnum(valueField: 'value')
+enum Level {
+  low(0),
+  high(1),
+  max(1);
+
+  const Level(this.value);
+
+  final int value;
+}
+
+@JsonSerializable(explicitToJson: true)
+class Order {
+  const Order({
+    required this.id,
+    required this.status,
+    this.level,
+    required this.items,
+    required this.labels,
+    required this.created,
+    this.note,
+    this.ref,
+  });
+
+  factory Order.fromJson(Map<String, dynamic> json) => _$OrderFromJson(json);
+
+  final String id;
+  final Status status;
+  @JsonKey(includeIfNull: false)
+  final Level? level;
+  final List<Item> items;
+  final Map<String, String> labels;
+  final DateTime created;
+  @JsonKey(includeIfNull: false)
+  final String? note;
+  final p2.S? ref;
+
+  Map<String, dynamic> toJson() => _$OrderToJson(this);
+}
+
+@JsonEnum(valueField: 'value')
+enum Status {
+  open(1),
+  closed(2);
+
+  const Status(this.value);
+
+  final int value;
+}
+`
+	files := tr.Render(types)
+	if len(files) != 2 || files[1].Path != "p5/types.dart" {
+		t.Fatal("unexpected")
+	}
+	if files[1].Content != want {
+		t.Errorf("unexpected\n%s", files[1].Content)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if got := quote(`it's $x`); got != `'it\'s \$x'` {
+		t.Errorf("unexpected %s", got)
+	}
+}
@@ -0,0 +1,103 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type (
+	// Diagnostic is an error reported while loading a package.
+	Diagnostic struct {
+		Package  string `json:"package"`  // import path
+		Position string `json:"position"` // file:line:col, or "-"
+		Kind     string `json:"kind"`     // list, parse, type or unknown
+		Message  string `json:"message"`
+	}
+
+	// Diagnostics is a list of diagnostics in load order, it
+	// implements the error interface reporting the diagnostics
+	// grouped by package.
+	Diagnostics []Diagnostic
+)
+
+// diagnose returns the errors of a package as diagnostics,
+// positions relative to the module root if possible.
+func diagnose(pkg *packages.Package) Diagnostics {
+	list := make(Diagnostics, 0, len(pkg.Errors))
+	for _, e := range pkg.Errors {
+		pos := e.Pos
+		if pos == "" {
+			pos = "-"
+		} else if pkg.Module != nil {
+			if rel, err := filepath.Rel(pkg.Module.Dir, pos); err == nil && !strings.HasPrefix(rel, "..") {
+				pos = filepath.ToSlash(rel)
+			}
+		}
+		kind := map[packages.ErrorKind]string{
+			packages.ListError:  "list",
+			packages.ParseError: "parse",
+			packages.TypeError:  "type",
+		}[e.Kind]
+		if kind == "" {
+			kind = "unknown"
+		}
+		list = append(list, Diagnostic{Package: pkg.PkgPath, Position: pos, Kind: kind, Message: e.Msg})
+	}
+	return list
+}
+
+// add appends the diagnostics not in the list yet, packages
+// loaded with their tests repeat the errors of the package.
+func (d Diagnostics) add(list Diagnostics) Diagnostics {
+	for _, diag := range list {
+		known := false
+		for _, e := range d {
+			known = known || e == diag
+		}
+		if !known {
+			d = append(d, diag)
+		}
+	}
+	return d
+}
+
+// Error returns a report of the diagnostics grouped by
+// package, the packages in order of their import paths.
+func (d Diagnostics) Error() string {
+	byPkg := make(map[string][]Diagnostic)
+	for _, diag := range d {
+		byPkg[diag.Package] = append(byPkg[diag.Package], diag)
+	}
+	pkgs := make([]string, 0, len(byPkg))
+	for pkg := range byPkg {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+
+	b := strings.Builder{}
+	b.WriteString(count(len(d), "error") + " in " + count(len(pkgs), "package"))
+	for _, pkg := range pkgs {
+		b.WriteString("\n    " + pkg)
+		for _, diag := range byPkg[pkg] {
+			b.WriteString("\n        " + diag.Position + ": " + diag.Kind + ": " + diag.Message)
+		}
+	}
+	return b.String()
+}
+
+func count(n int, noun string) string {
+	switch {
+	case n == 1:
+	case strings.HasSuffix(noun, "s"):
+		noun += "es"
+	default:
+		noun += "s"
+	}
+	return strconv.Itoa(n) + " " + noun
+}
@@ -0,0 +1,26 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import "testing"
+
+func TestDiagnostics_Error(t *testing.T) {
+	d := Diagnostics{
+		{Package: "b", Position: "b.go:1:2", Kind: "type", Message: "x"},
+		{Package: "a", Position: "-", Kind: "list", Message: "y"},
+		{Package: "b", Position: "b.go:3:4", Kind: "parse", Message: "z"},
+	}
+	want := "3 errors in 2 packages" +
+		"\n    a" +
+		"\n        -: list: y" +
+		"\n    b" +
+		"\n        b.go:1:2: type: x" +
+		"\n        b.go:3:4: parse: z"
+
+	if s := d.Error(); s != want {
+		t.Errorf("unexpected %q", s)
+	}
+	if s := d[:1].Error(); s != "1 error in 1 package\n    b\n        b.go:1:2: type: x" {
+		t.Errorf("unexpected %q", s)
+	}
+}
@@ -0,0 +1,120 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"strings"
+)
+
+type (
+	// Directive is a comment directive attached to a type declaration,
+	// e.g. "//typex:export ts name=UserDTO" or "//typex:skip go".
+	Directive struct {
+		Verb    string            // "export" or "skip".
+		Layout  string            // Layout scope, empty for all layouts.
+		Options map[string]string // Layout specific options, e.g. name.
+	}
+
+	// Directives maps qualified type names to their directives.
+	Directives map[string][]Directive
+)
+
+// DirectivePrefix introduces a directive comment.
+const DirectivePrefix = "//typex:"
+
+// ParseDirective parses a directive comment line. The first argument
+// following the verb is the layout scope, unless it is an option in
+// the form of key=value. Options without a value are set to "true".
+func ParseDirective(line string) (Directive, bool) {
+	if !strings.HasPrefix(line, DirectivePrefix) {
+		return Directive{}, false
+	}
+	args := strings.Fields(line[len(DirectivePrefix):])
+	if len(args) == 0 {
+		return Directive{}, false
+	}
+	switch args[0] {
+	case "export", "skip":
+	default:
+		return Directive{}, false
+	}
+
+	d := Directive{Verb: args[0], Options: make(map[string]string)}
+	for i, arg := range args[1:] {
+		kv := strings.SplitN(arg, "=", 2)
+		switch {
+		case len(kv) == 2:
+			d.Options[kv[0]] = kv[1]
+		case i == 0:
+			d.Layout = arg
+		default:
+			d.Options[arg] = "true"
+		}
+	}
+	return d, true
+}
+
+// Matches reports whether the directive applies to the given layout.
+// A scope applies to layouts of the same name and to layouts prefixed
+// by the scope and a dash, i.e. "ts" applies to "ts-type" as well.
+func (d Directive) Matches(layout string) bool {
+	return d.Layout == "" || d.Layout == layout || strings.HasPrefix(layout, d.Layout+"-")
+}
+
+// IsMarked reports whether the type carries an export directive.
+func (d Directives) IsMarked(name string) bool {
+	for _, dir := range d[name] {
+		if dir.Verb == "export" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSkipped reports whether the type is to be skipped for the layout.
+func (d Directives) IsSkipped(name, layout string) bool {
+	for _, dir := range d[name] {
+		if dir.Matches(layout) && (dir.Verb == "skip" || dir.Options["skip"] == "true") {
+			return true
+		}
+	}
+	return false
+}
+
+// Option returns the value of a directive option for the layout.
+// Options of a more specific layout scope take precedence.
+func (d Directives) Option(name, layout, key string) (string, bool) {
+	val, found, scope := "", false, -1
+	for _, dir := range d[name] {
+		v, ok := dir.Options[key]
+		if ok && dir.Matches(layout) && len(dir.Layout) > scope {
+			val, found, scope = v, true, len(dir.Layout)
+		}
+	}
+	return val, found
+}
+
+// Prune removes the types to be skipped for the layout from the TypeMap.
+func (d Directives) Prune(layout string, m TypeMap) TypeMap {
+	pruned := make(TypeMap, len(m))
+	for name, t := range m {
+		if !d.IsSkipped(name, layout) {
+			pruned[name] = t
+		}
+	}
+	return pruned
+}
+
+// RenameFunc returns a PathReplaceFunc applying the type name
+// overrides for the layout prior to the path replacement f.
+func (d Directives) RenameFunc(layout string, f PathReplaceFunc) PathReplaceFunc {
+	return func(s string) string {
+		if name, ok := d.Option(s, layout, "name"); ok {
+			s = s[:strings.LastIndex(s, ".")+1] + name
+		}
+		if f != nil {
+			return f(s)
+		}
+		return s
+	}
+}
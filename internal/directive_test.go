@@ -0,0 +1,76 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dtgorski/typex/internal/testdata/p4"
+)
+
+func TestParseDirective(t *testing.T) {
+	for i, c := range []struct {
+		line string
+		want Directive
+		ok   bool
+	}{
+		{"//typex:export", Directive{Verb: "export", Options: map[string]string{}}, true},
+		{"//typex:export ts name=X", Directive{"export", "ts", map[string]string{"name": "X"}}, true},
+		{"//typex:export name=X skip", Directive{"export", "", map[string]string{"name": "X", "skip": "true"}}, true},
+		{"//typex:skip go", Directive{"skip", "go", map[string]string{}}, true},
+		{"//typex:unknown", Directive{}, false},
+		{"//typex:", Directive{}, false},
+		{"// typex:export", Directive{}, false},
+	} {
+		d, ok := ParseDirective(c.line)
+		if ok != c.ok || !reflect.DeepEqual(d, c.want) {
+			t.Errorf("%d: unexpected %+v", i, d)
+		}
+	}
+}
+
+func TestPackagist_SelectMarked(t *testing.T) {
+	pkgPath := reflect.TypeOf(p4.A{}).PkgPath()
+
+	pac := Packagist{SelectMarked: true}
+	types, err := pac.Inspect(pkgPath)
+	if err != nil {
+		t.Error("unexpected")
+		return
+	}
+
+	roots := make([]string, 0)
+	for name, depth := range pac.depth {
+		if depth == 0 {
+			roots = append(roots, strings.TrimPrefix(name, pkgPath+"."))
+		}
+	}
+	sort.Strings(roots)
+	if strings.Join(roots, " ") != "A C" || len(types) != 3 {
+		t.Errorf("unexpected %v", roots)
+	}
+
+	dirs := pac.Directives()
+	if !dirs.IsSkipped(pkgPath+".B", "ts-type") || dirs.IsSkipped(pkgPath+".B", "go") {
+		t.Error("unexpected")
+	}
+	if m := dirs.Prune("ts-class", types); len(m) != 2 {
+		t.Error("unexpected")
+	}
+
+	rename := dirs.RenameFunc("ts-type", CreatePathReplaceFunc([]string{".*/testdata/:"}))
+	if s := rename(pkgPath + ".C"); s != "p4.DTO" {
+		t.Errorf("unexpected %s", s)
+	}
+	rename = dirs.RenameFunc("ts-class", nil)
+	if s := rename(pkgPath + ".C"); s != pkgPath+".VO" {
+		t.Errorf("unexpected %s", s)
+	}
+	rename = dirs.RenameFunc("go", nil)
+	if s := rename(pkgPath + ".C"); s != pkgPath+".C" {
+		t.Errorf("unexpected %s", s)
+	}
+}
@@ -0,0 +1,65 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package dot
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	typex "github.com/dtgorski/typex/internal"
+)
+
+type (
+	graphLayout struct {
+		writer io.Writer
+		edges  []string
+		indent int
+	}
+)
+
+// NewGraphLayout implements the Layout interface. Packages are
+// rendered as clusters containing the nodes of their types. The
+// edge statements are emitted after the clusters, because nodes
+// first mentioned in an edge statement in a cluster would become
+// members of that cluster.
+func NewGraphLayout(w io.Writer, edges []string) typex.Layout {
+	return &graphLayout{writer: w, edges: edges, indent: 1}
+}
+
+func (g *graphLayout) Open() {
+	g.write(g.writer, "digraph typex {\n")
+	g.write(g.writer, "    rankdir=LR;\n")
+	g.write(g.writer, "    node [fontname=\"Helvetica\"];\n")
+	g.write(g.writer, "    edge [fontname=\"Helvetica\", fontsize=10];\n")
+}
+
+func (g *graphLayout) Enter(path string, _ bool) {
+	i := strings.LastIndex(path, "/")
+	p := strings.Repeat(" ", g.indent<<2)
+	g.write(g.writer, "%ssubgraph %s {\n", p, quote("cluster_"+path))
+	g.write(g.writer, "%s    label=%s;\n", p, quote(path[i+1:]))
+	g.indent++
+}
+
+func (g *graphLayout) Print(line string, _, _ bool) {
+	p := strings.Repeat(" ", g.indent<<2)
+	g.write(g.writer, "%s%s\n", p, line)
+}
+
+func (g *graphLayout) Leave(_ string, _ bool) {
+	g.indent--
+	p := strings.Repeat(" ", g.indent<<2)
+	g.write(g.writer, "%s}\n", p)
+}
+
+func (g *graphLayout) Close() {
+	for _, edge := range g.edges {
+		g.write(g.writer, "    %s\n", edge)
+	}
+	g.write(g.writer, "}\n")
+}
+
+func (graphLayout) write(w io.Writer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
@@ -0,0 +1,58 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package dot
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p1"
+)
+
+// typex -l="dot" -r=".*/testdata:" ./internal/testdata/p1 > ./internal/testdata/fixture-dot.txt
+func TestNewGraphLayout(t *testing.T) {
+	fixture := "../testdata/fixture-dot.txt"
+
+	pac := typex.Packagist{}
+	path := reflect.TypeOf(p1.D{}).PkgPath()
+
+	types, err := pac.Inspect(path)
+	if err != nil {
+		t.Error("unexpected")
+	}
+
+	re := typex.CreatePathReplaceFunc([]string{".*/testdata:"})
+	tr := TypeRender{PathReplaceFunc: re}
+
+	buf := &bytes.Buffer{}
+	tw := typex.TreeWalk{Layout: NewGraphLayout(buf, tr.Edges(types, nil))}
+
+	if err := tw.Walk(tr.Render(types)); err != nil {
+		t.Error("unexpected")
+	}
+
+	fix, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Error("unexpected")
+	}
+	if !bytes.Equal(fix, buf.Bytes()) {
+		tmp, err := ioutil.TempFile("", "")
+		if err != nil {
+			t.Error(err)
+		}
+		defer func() { _ = os.Remove(tmp.Name()) }()
+
+		err = ioutil.WriteFile(tmp.Name(), buf.Bytes(), 0644)
+		if err != nil {
+			t.Error(err)
+		}
+		cmd := exec.Command("diff", "-u", fixture, tmp.Name())
+		out, _ := cmd.Output()
+		t.Error(string(out))
+	}
+}
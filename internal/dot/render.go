@@ -0,0 +1,98 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package dot
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	typex "github.com/dtgorski/typex/internal"
+)
+
+type (
+	// TypeRender renders Go types as Graphviz DOT nodes and edges.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+	}
+)
+
+// Render converts a TypeMap to a PathMap. Each type is rendered as
+// a node, the edges between the nodes are rendered by Edges.
+func (r *TypeRender) Render(m typex.TypeMap) typex.PathMap {
+	pathMap := make(typex.PathMap)
+
+	for p, t := range m {
+		path, name := r.names().PathAndName(p)
+
+		shape := "box"
+		if _, ok := t.Underlying().(*types.Interface); ok {
+			shape = "ellipse"
+		}
+		pathMap[path] = fmt.Sprintf("%s [label=%s, shape=%s];", r.id(p), quote(name), shape)
+	}
+	return pathMap
+}
+
+// Edges returns the edge statements from the types of the TypeMap to
+// the types they refer to, ordered by the Ranking of the referring
+// types, if any, otherwise by their paths. Edges to types not
+// contained in the TypeMap are omitted.
+func (r *TypeRender) Edges(m typex.TypeMap, ranking typex.Ranking) []string {
+	keys := make([]string, 0, len(m))
+	for p := range m {
+		keys = append(keys, p)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return r.Path(keys[i]) < r.Path(keys[j])
+	})
+	ranking.Sort(keys)
+
+	edges := make([]string, 0)
+	for _, p := range keys {
+		nt, ok := m[p].(*types.Named)
+		if !ok {
+			continue
+		}
+		for _, ref := range typex.References(nt, r.IncludeUnexported) {
+			if _, ok := m[ref.To.String()]; !ok {
+				continue
+			}
+			edges = append(edges, fmt.Sprintf("%s -> %s [%s];", r.id(p), r.id(ref.To.String()), r.attrs(ref)))
+		}
+	}
+	return edges
+}
+
+// Path returns the PathMap key of a qualified type name.
+func (r *TypeRender) Path(s string) string {
+	p, _ := r.names().PathAndName(s)
+	return p
+}
+
+func (r *TypeRender) attrs(ref typex.Reference) string {
+	label := quote(ref.Field)
+	switch ref.Kind {
+	case typex.RefEmbedded:
+		return "label=" + label + ", arrowhead=onormal"
+	case typex.RefElement:
+		return "label=" + label + ", style=dotted"
+	case typex.RefSignature:
+		return "label=" + label + ", style=dashed"
+	}
+	return "label=" + label
+}
+
+func (r *TypeRender) id(s string) string {
+	return quote(r.names().Replace(s))
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func (r *TypeRender) names() typex.Names {
+	return typex.Names{PathReplaceFunc: r.PathReplaceFunc, IncludeUnexported: r.IncludeUnexported}
+}
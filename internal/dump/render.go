@@ -0,0 +1,205 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package dump
+
+import (
+	"encoding/json"
+	"go/types"
+	"io"
+	"sort"
+
+	typex "github.com/dtgorski/typex/internal"
+)
+
+type (
+	// TypeRender renders Go types as JSON serializable declarations.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		PositionFunc      typex.PositionFunc
+		IncludeUnexported bool
+		IncludeMethods    bool
+		Ranking           typex.Ranking
+		SortFields        bool
+	}
+
+	// Decl is a named type declaration.
+	Decl struct {
+		Name     string   `json:"name"`
+		Kind     string   `json:"kind"`
+		Type     string   `json:"type"`
+		Position string   `json:"position,omitempty"`
+		Fields   []Field  `json:"fields,omitempty"`
+		Methods  []Method `json:"methods,omitempty"`
+	}
+
+	// Field is a struct field of a declaration.
+	Field struct {
+		Name     string `json:"name"`
+		Type     string `json:"type"`
+		Tag      string `json:"tag,omitempty"`
+		Embedded bool   `json:"embedded,omitempty"`
+	}
+
+	// Method is a method declared on a named type.
+	Method struct {
+		Name    string `json:"name"`
+		Type    string `json:"type"`
+		Pointer bool   `json:"pointer,omitempty"`
+	}
+
+	// Implementer lists the interfaces a concrete type implements,
+	// or the concrete types implementing an interface respectively.
+	Implementer struct {
+		Name          string     `json:"name"`
+		Kind          string     `json:"kind"`
+		Implements    []Relation `json:"implements,omitempty"`
+		ImplementedBy []Relation `json:"implementedBy,omitempty"`
+	}
+
+	// Relation refers to a type implementing or being implemented.
+	// Pointer denotes an implementation by a pointer receiver.
+	Relation struct {
+		Name    string `json:"name"`
+		Pointer bool   `json:"pointer,omitempty"`
+	}
+
+	// Referrer is a type referring to another type.
+	Referrer struct {
+		Name      string     `json:"name"`
+		Field     string     `json:"field,omitempty"`
+		Referrers []Referrer `json:"referrers"`
+		Repeated  bool       `json:"repeated,omitempty"`
+	}
+)
+
+// Render converts a TypeMap to a list of declarations ordered
+// by name, or by the Ranking, if any.
+func (r *TypeRender) Render(m typex.TypeMap) []Decl {
+	decls, ranks := make([]Decl, 0, len(m)), make(typex.Ranking)
+
+	for p, t := range m {
+		typ := t.Underlying()
+		decl := Decl{
+			Name: r.names().Replace(p),
+			Kind: typex.KindOf(typ),
+			Type: types.TypeString(typ, r.qualifier),
+		}
+		ranks.Put(decl.Name, r.Ranking.Of(p))
+		if s, ok := typ.(*types.Struct); ok {
+			decl.Fields = r.fields(s)
+		}
+		if nt, ok := t.(*types.Named); ok && r.IncludeMethods {
+			decl.Methods = r.methods(nt)
+		}
+		if nt, ok := t.(*types.Named); ok && r.PositionFunc != nil {
+			decl.Position = r.PositionFunc(nt)
+		}
+		decls = append(decls, decl)
+	}
+	sort.Slice(decls, func(i, j int) bool {
+		if a, b := ranks.Of(decls[i].Name), ranks.Of(decls[j].Name); a != b {
+			return a < b
+		}
+		return decls[i].Name < decls[j].Name
+	})
+	return decls
+}
+
+// RenderReverse converts chains of referencing types to a JSON serializable list.
+func (r *TypeRender) RenderReverse(refs []*typex.Referrer) []Referrer {
+	list := make([]Referrer, 0, len(refs))
+
+	for _, ref := range refs {
+		list = append(list, Referrer{
+			Name:      r.names().Replace(ref.Type.String()),
+			Field:     ref.Field,
+			Referrers: r.RenderReverse(ref.Referrers),
+			Repeated:  ref.Repeated,
+		})
+	}
+	return list
+}
+
+// RenderImplementations converts the interface implementations of the
+// types in the TypeMap to a JSON serializable list ordered by name.
+func (r *TypeRender) RenderImplementations(m typex.TypeMap, impls []typex.Implementation) []Implementer {
+	list := make([]Implementer, 0)
+
+	for p, t := range m {
+		impl := Implementer{
+			Name: r.names().Replace(p),
+			Kind: typex.KindOf(t.Underlying()),
+		}
+		for _, i := range impls {
+			if i.Interface.String() == p {
+				rel := Relation{r.names().Replace(i.Type.String()), i.Pointer}
+				impl.ImplementedBy = append(impl.ImplementedBy, rel)
+			}
+			if i.Type.String() == p {
+				rel := Relation{r.names().Replace(i.Interface.String()), i.Pointer}
+				impl.Implements = append(impl.Implements, rel)
+			}
+		}
+		if len(impl.Implements) > 0 || len(impl.ImplementedBy) > 0 {
+			list = append(list, impl)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Write encodes v as indented JSON.
+func Write(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	return enc.Encode(v)
+}
+
+func (r *TypeRender) fields(t *types.Struct) []Field {
+	fields := make([]Field, 0, t.NumFields())
+
+	for _, i := range typex.FieldOrder(t, r.SortFields) {
+		f := t.Field(i)
+		if !r.names().IsExported(f.Name()) {
+			continue
+		}
+		fields = append(fields, Field{
+			Name:     f.Name(),
+			Type:     types.TypeString(f.Type(), r.qualifier),
+			Tag:      t.Tag(i),
+			Embedded: f.Embedded(),
+		})
+	}
+	return fields
+}
+
+func (r *TypeRender) methods(t *types.Named) []Method {
+	methods := make([]Method, 0, t.NumMethods())
+
+	for i, n := 0, t.NumMethods(); i < n; i++ {
+		m := t.Method(i)
+		if !r.names().IsExported(m.Name()) {
+			continue
+		}
+		sig := m.Type().(*types.Signature)
+		_, ptr := sig.Recv().Type().(*types.Pointer)
+
+		methods = append(methods, Method{
+			Name:    m.Name(),
+			Type:    types.TypeString(sig, r.qualifier),
+			Pointer: ptr,
+		})
+	}
+	return methods
+}
+
+func (r *TypeRender) qualifier(p *types.Package) string {
+	return r.names().Replace(p.Path())
+}
+
+func (r *TypeRender) names() typex.Names {
+	return typex.Names{PathReplaceFunc: r.PathReplaceFunc, IncludeUnexported: r.IncludeUnexported}
+}
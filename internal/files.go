@@ -0,0 +1,43 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type (
+	// File is a generated source file. The Path is relative
+	// to the output directory and uses forward slashes.
+	File struct {
+		Path    string
+		Content string
+	}
+)
+
+// WriteFiles stores the files below the directory dir. When dir is
+// empty, the files are written to w instead, each one preceded by a
+// header line naming the file, which starts with the line comment
+// token of the target language.
+func WriteFiles(w io.Writer, dir, comment string, files []File) error {
+	for _, f := range files {
+		if dir == "" {
+			if _, err := fmt.Fprintf(w, "%s file: %s\n%s", comment, f.Path, f.Content); err != nil {
+				return err
+			}
+			continue
+		}
+		name := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(name, []byte(f.Content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package g0
+
+import (
+	"bytes"
+	"go/types"
+
+	typex "github.com/dtgorski/typex/internal"
+)
+
+// RenderImplementations converts the interface implementations
+// of the types in the TypeMap to a PathMap. Types neither being
+// implemented nor implementing an interface are omitted.
+func (r *TypeRender) RenderImplementations(m typex.TypeMap, impls []typex.Implementation) typex.PathMap {
+	r.indent = 0
+	pathMap := make(typex.PathMap)
+
+	for p, t := range m {
+		nt, ok := t.(*types.Named)
+		if !ok {
+			continue
+		}
+		path, name := r.names().PathAndName(p)
+		buf := bytes.Buffer{}
+		ctx := context{writer: &buf}
+		_, isIface := nt.Underlying().(*types.Interface)
+
+		r.indent++
+		for _, impl := range impls {
+			switch {
+			case isIface && impl.Interface.String() == p:
+				r.write(ctx, "\n")
+				r.writePadding(ctx)
+				r.write(ctx, "implemented by ")
+				if impl.Pointer {
+					r.write(ctx, "*")
+				}
+				r.writeNamed(ctx, impl.Type)
+
+			case !isIface && impl.Type.String() == p:
+				r.write(ctx, "\n")
+				r.writePadding(ctx)
+				r.write(ctx, "implements ")
+				r.writeNamed(ctx, impl.Interface)
+				if impl.Pointer {
+					r.write(ctx, " (pointer receiver)")
+				}
+			}
+		}
+		r.indent--
+
+		if buf.Len() > 0 {
+			pathMap[path] = name + " " + typex.KindOf(nt.Underlying()) + buf.String()
+		}
+	}
+	return pathMap
+}
@@ -6,7 +6,6 @@ package g0
 import (
 	"bytes"
 	"fmt"
-	"go/token"
 	"go/types"
 	"io"
 	"strings"
@@ -18,7 +17,10 @@ type (
 	// TypeRender renders Go types.
 	TypeRender struct {
 		PathReplaceFunc   typex.PathReplaceFunc
+		PositionFunc      typex.PositionFunc
 		IncludeUnexported bool
+		IncludeMethods    bool
+		SortFields        bool
 
 		indent int
 	}
@@ -35,16 +37,30 @@ func (r *TypeRender) Render(m typex.TypeMap) typex.PathMap {
 	pathMap := make(typex.PathMap)
 
 	for p, t := range m {
-		path, name := r.pathAndName(p)
+		path, name := r.names().PathAndName(p)
 		buf, typ := bytes.Buffer{}, t.Underlying()
 		ctx := context{&buf, make([]types.Type, 0)}
 
 		r.writeType(ctx, typ)
-		pathMap[path] = name + " " + buf.String()
+		nt, isNamed := t.(*types.Named)
+		if isNamed && r.IncludeMethods {
+			r.writeMethods(ctx, nt)
+		}
+		decl := name + " " + buf.String()
+		if isNamed && r.PositionFunc != nil {
+			decl = r.withPosition(decl, r.PositionFunc(nt))
+		}
+		pathMap[path] = decl
 	}
 	return pathMap
 }
 
+// Path returns the PathMap key of a qualified type name.
+func (r *TypeRender) Path(s string) string {
+	p, _ := r.names().PathAndName(s)
+	return p
+}
+
 func (r *TypeRender) writeType(ctx context, t types.Type) {
 	for _, tt := range ctx.seenType {
 		if tt == t {
@@ -53,7 +69,7 @@ func (r *TypeRender) writeType(ctx context, t types.Type) {
 	}
 	ctx.seenType = append(ctx.seenType, t)
 
-	switch tt := t.(type) {
+	switch tt := typex.Unalias(t).(type) {
 	case *types.Basic:
 		r.write(ctx, tt.Name())
 
@@ -87,9 +103,25 @@ func (r *TypeRender) writeType(ctx context, t types.Type) {
 
 	case *types.Struct:
 		r.writeStruct(ctx, tt)
+
+	case *types.Union:
+		r.writeUnion(ctx, tt)
 	}
 }
 
+// withPosition appends the position as a trailing
+// comment to the first line of the declaration.
+func (r *TypeRender) withPosition(decl, pos string) string {
+	if pos == "" {
+		return decl
+	}
+	i := strings.Index(decl, "\n")
+	if i < 0 {
+		return decl + "  // " + pos
+	}
+	return decl[:i] + "  // " + pos + decl[i:]
+}
+
 func (r *TypeRender) writeChan(ctx context, t *types.Chan) {
 	s := ""
 	switch t.Dir() {
@@ -110,7 +142,7 @@ func (r *TypeRender) writeInterface(ctx context, t *types.Interface) {
 
 	void := true
 	for i, n := 0, t.NumEmbeddeds(); i < n; i++ {
-		if !r.isExported(t.EmbeddedType(i).String()) {
+		if nt, ok := t.EmbeddedType(i).(*types.Named); ok && !r.names().IsExported(nt.String()) {
 			continue
 		}
 		r.write(ctx, "\n")
@@ -119,7 +151,7 @@ func (r *TypeRender) writeInterface(ctx context, t *types.Interface) {
 		void = false
 	}
 	for i, n := 0, t.NumMethods(); i < n; i++ {
-		if !r.isExported(t.Method(i).Name()) {
+		if !r.names().IsExported(t.Method(i).Name()) {
 			continue
 		}
 		r.write(ctx, "\n")
@@ -135,6 +167,18 @@ func (r *TypeRender) writeInterface(ctx context, t *types.Interface) {
 	r.write(ctx, "}")
 }
 
+func (r *TypeRender) writeUnion(ctx context, t *types.Union) {
+	for i, n := 0, t.Len(); i < n; i++ {
+		if i > 0 {
+			r.write(ctx, " | ")
+		}
+		if t.Term(i).Tilde() {
+			r.write(ctx, "~")
+		}
+		r.writeType(ctx, t.Term(i).Type())
+	}
+}
+
 func (r *TypeRender) writeMap(ctx context, t *types.Map) {
 	r.write(ctx, "map[")
 	r.writeType(ctx, t.Key())
@@ -149,11 +193,43 @@ func (r *TypeRender) writeNamed(ctx context, t *types.Named) {
 			p = obj.Pkg().Path() + "."
 		}
 		p += obj.Name()
-		p = r.replacePath(p)
+		p = r.names().Replace(p)
 		i := strings.LastIndex(p, "/")
 		p = p[i+1:]
 	}
 	r.write(ctx, p)
+
+	if args := t.TypeArgs(); args.Len() > 0 {
+		r.write(ctx, "[")
+		for i := 0; i < args.Len(); i++ {
+			if i > 0 {
+				r.write(ctx, ", ")
+			}
+			r.writeType(ctx, args.At(i))
+		}
+		r.write(ctx, "]")
+	}
+}
+
+func (r *TypeRender) writeMethods(ctx context, t *types.Named) {
+	r.indent++
+	for i, n := 0, t.NumMethods(); i < n; i++ {
+		m := t.Method(i)
+		if !r.names().IsExported(m.Name()) {
+			continue
+		}
+		sig := m.Type().(*types.Signature)
+		r.write(ctx, "\n")
+		r.writePadding(ctx)
+		r.write(ctx, "func (")
+		if _, ok := sig.Recv().Type().(*types.Pointer); ok {
+			r.write(ctx, "*")
+		}
+		r.write(ctx, t.Obj().Name())
+		r.write(ctx, ") %s", m.Name())
+		r.writeSignature(ctx, sig)
+	}
+	r.indent--
 }
 
 func (r *TypeRender) writeStruct(ctx context, t *types.Struct) {
@@ -161,12 +237,12 @@ func (r *TypeRender) writeStruct(ctx context, t *types.Struct) {
 	r.indent++
 	r.write(ctx, "struct {")
 
-	for i, n := 0, t.NumFields(); i < n; i++ {
-		if !r.isExported(t.Field(i).Name()) {
+	for _, i := range typex.FieldOrder(t, r.SortFields) {
+		if !r.names().IsExported(t.Field(i).Name()) {
 			continue
 		}
 		if tt, ok := t.Field(i).Type().(*types.Named); ok {
-			if !r.isExported(tt.String()) {
+			if !r.names().IsExported(tt.String()) {
 				continue
 			}
 		}
@@ -242,29 +318,6 @@ func (TypeRender) write(ctx context, f string, a ...interface{}) {
 	_, _ = fmt.Fprintf(ctx.writer, f, a...)
 }
 
-func (r *TypeRender) pathAndName(s string) (p, n string) {
-	p = r.replacePath(s)
-	n = p
-	i := strings.LastIndex(p, ".")
-	if i > -1 && i < len(p)-1 {
-		n = p[i+1:]
-		p = p[:i] + "/" + n
-	}
-	return p, n
-}
-
-func (r *TypeRender) replacePath(s string) string {
-	if r.PathReplaceFunc != nil {
-		return r.PathReplaceFunc(s)
-	}
-	return s
-}
-
-func (r *TypeRender) isExported(s string) bool {
-	if r.IncludeUnexported {
-		return true
-	}
-	n := strings.ReplaceAll(s, ".", "/")
-	i := strings.LastIndex(n, "/")
-	return token.IsExported(n[i+1:])
+func (r *TypeRender) names() typex.Names {
+	return typex.Names{PathReplaceFunc: r.PathReplaceFunc, IncludeUnexported: r.IncludeUnexported}
 }
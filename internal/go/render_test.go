@@ -0,0 +1,34 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package g0
+
+import (
+	"reflect"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p4"
+)
+
+func TestTypeRender_IncludeMethods(t *testing.T) {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p4\.A$`}, nil),
+	}
+	path := reflect.TypeOf(p4.A{}).PkgPath()
+
+	types, err := pac.Inspect(path)
+	if err != nil {
+		t.Error("unexpected")
+	}
+
+	re := typex.CreatePathReplaceFunc([]string{".*/testdata:"})
+	tr := TypeRender{PathReplaceFunc: re, IncludeMethods: true}
+
+	want := "A struct {\n    B p4.B\n}\n" +
+		"    func (A) String() string\n" +
+		"    func (*A) Set(string) error"
+
+	if got := tr.Render(types)["p4/A"]; got != want {
+		t.Errorf("unexpected\n%s", got)
+	}
+}
@@ -0,0 +1,45 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package g0
+
+import (
+	"bytes"
+
+	typex "github.com/dtgorski/typex/internal"
+)
+
+// RenderReverse converts chains of referencing types to a PathMap.
+func (r *TypeRender) RenderReverse(refs []*typex.Referrer) typex.PathMap {
+	r.indent = 0
+	pathMap := make(typex.PathMap)
+
+	for _, ref := range refs {
+		path, name := r.names().PathAndName(ref.Type.String())
+		buf := bytes.Buffer{}
+		ctx := context{writer: &buf}
+
+		r.write(ctx, name)
+		r.writeReferrers(ctx, ref.Referrers)
+		pathMap[path] = buf.String()
+	}
+	return pathMap
+}
+
+func (r *TypeRender) writeReferrers(ctx context, refs []*typex.Referrer) {
+	r.indent++
+	for _, ref := range refs {
+		r.write(ctx, "\n")
+		r.writePadding(ctx)
+		r.write(ctx, "← ")
+		r.writeNamed(ctx, ref.Type)
+		if ref.Field != "" {
+			r.write(ctx, ".%s", ref.Field)
+		}
+		if ref.Repeated {
+			r.write(ctx, " (see above)")
+			continue
+		}
+		r.writeReferrers(ctx, ref.Referrers)
+	}
+	r.indent--
+}
@@ -0,0 +1,138 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+type (
+	// Reference is an edge from a named type to a named type
+	// it uses. Field denotes the location of the usage within
+	// the referencing type, it is empty for direct usage. The
+	// Multiplicity is derived from pointers ("0..1"), slices,
+	// arrays and maps ("*"), otherwise it is "1".
+	Reference struct {
+		From         *types.Named
+		Field        string
+		To           *types.Named
+		Kind         RefKind
+		Multiplicity string
+	}
+
+	// RefKind classifies a Reference by its origin.
+	RefKind int
+
+	referrer struct {
+		refs []Reference
+		from *types.Named
+		all  bool
+	}
+)
+
+// Reference kinds.
+const (
+	RefField     RefKind = iota // struct field
+	RefEmbedded                 // embedded struct field or interface
+	RefElement                  // element, key or base of a composite type
+	RefSignature                // parameter or result of a method or function
+)
+
+// References returns the references from the named type to the named
+// types used in its declaration. Unexported fields and methods are
+// skipped unless includeUnexported is set. The methods declared on a
+// named type are left out, only the method set of an interface is
+// part of its declaration: the graphs show the shape of the data,
+// method signatures would connect most types to error, context and
+// the like.
+func References(t *types.Named, includeUnexported bool) []Reference {
+	r := &referrer{
+		refs: make([]Reference, 0),
+		from: t,
+		all:  includeUnexported,
+	}
+	r.visit("", RefElement, "1", t.Underlying())
+	return r.refs
+}
+
+func (r *referrer) visit(field string, kind RefKind, multi string, t types.Type) {
+	switch tt := Unalias(t).(type) {
+	case *types.Array:
+		r.visit(field, kind, "*", tt.Elem())
+
+	case *types.Chan:
+		r.visit(field, kind, multi, tt.Elem())
+
+	case *types.Interface:
+		for i, n := 0, tt.NumExplicitMethods(); i < n; i++ {
+			if m := tt.ExplicitMethod(i); r.isExported(m.Name()) {
+				r.visit(join(field, m.Name()+"()"), RefSignature, "1", m.Type())
+			}
+		}
+		for i, n := 0, tt.NumEmbeddeds(); i < n; i++ {
+			r.visit(field, RefEmbedded, "1", tt.EmbeddedType(i))
+		}
+
+	case *types.Map:
+		r.visit(field, kind, "*", tt.Key())
+		r.visit(field, kind, "*", tt.Elem())
+
+	case *types.Named:
+		ref := Reference{r.from, field, tt, kind, multi}
+		for _, e := range r.refs {
+			if e == ref {
+				return
+			}
+		}
+		r.refs = append(r.refs, ref)
+
+	case *types.Pointer:
+		if multi == "1" {
+			multi = "0..1"
+		}
+		r.visit(field, kind, multi, tt.Elem())
+
+	case *types.Signature:
+		r.visit(field, RefSignature, "1", tt.Params())
+		r.visit(field, RefSignature, "1", tt.Results())
+
+	case *types.Slice:
+		r.visit(field, kind, "*", tt.Elem())
+
+	case *types.Struct:
+		for i, n := 0, tt.NumFields(); i < n; i++ {
+			f := tt.Field(i)
+			if !r.isExported(f.Name()) {
+				continue
+			}
+			if f.Embedded() {
+				r.visit(join(field, f.Name()), RefEmbedded, multi, f.Type())
+			} else {
+				r.visit(join(field, f.Name()), RefField, multi, f.Type())
+			}
+		}
+
+	case *types.Tuple:
+		for i, n := 0, tt.Len(); i < n; i++ {
+			r.visit(field, kind, multi, tt.At(i).Type())
+		}
+	}
+}
+
+func (r *referrer) isExported(s string) bool {
+	if r.all {
+		return true
+	}
+	n := strings.ReplaceAll(s, ".", "/")
+	i := strings.LastIndex(n, "/")
+	return token.IsExported(n[i+1:])
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
@@ -0,0 +1,331 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package graphql
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
+)
+
+type (
+	// TypeRender renders Go types as GraphQL schema definition
+	// language (SDL) declarations.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		InputFilterFunc   typex.PathFilterFunc
+		IncludeUnexported bool
+		TagKey            string
+		Scalar            string
+		Ranking           typex.Ranking
+		SortFields        bool
+
+		decls    map[string]string
+		ranks    typex.Ranking
+		rank     int
+		scalars  map[string]bool
+		inputs   []*model.Decl
+		warnings []string
+	}
+
+	context struct {
+		decl  string
+		input bool
+	}
+)
+
+// DefaultScalar is the custom scalar unmappable types are mapped to.
+const DefaultScalar = "JSON"
+
+// Render converts a TypeMap to a GraphQL schema. Structs are rendered
+// as object types, or as input types when matched by InputFilterFunc,
+// which applies to the structs referenced by input types as well.
+// Enumerations are rendered as enums, references to other named types
+// resolve to the aliased type. Maps, channels, functions and
+// interfaces are mapped to a custom scalar, a warning is returned for
+// each of these fields.
+func (r *TypeRender) Render(m typex.TypeMap) (string, []string) {
+	r.decls = make(map[string]string)
+	r.ranks = make(typex.Ranking)
+	r.scalars = make(map[string]bool)
+	r.inputs = make([]*model.Decl, 0)
+	r.warnings = make([]string, 0)
+
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		Ranking:           r.Ranking,
+		SortFields:        r.SortFields,
+	}
+	for _, d := range b.Build(m).Decls {
+		r.rank = r.Ranking.Of(d.Origin.String())
+
+		switch d.Kind {
+		case model.Struct:
+			if !r.isInput(d) {
+				r.writeObject(d.Name, d, context{})
+			} else {
+				r.inputs = append(r.inputs, d)
+			}
+		case model.Enum:
+			r.writeEnum(d)
+		case model.Alias:
+			for _, n := range d.Nested {
+				r.writeObject(n.Flat(), n, context{})
+			}
+		}
+	}
+
+	done := make(map[*model.Decl]bool)
+	for len(r.inputs) > 0 {
+		d := r.inputs[0]
+		r.inputs = r.inputs[1:]
+		if done[d] {
+			continue
+		}
+		done[d] = true
+		r.rank = r.Ranking.Of(d.Origin.String())
+		r.writeObject(r.inputName(d), d, context{input: true})
+	}
+
+	buf := bytes.Buffer{}
+	scalars := make([]string, 0, len(r.scalars))
+	for s := range r.scalars {
+		scalars = append(scalars, s)
+	}
+	sort.Strings(scalars)
+	for _, s := range scalars {
+		r.write(&buf, "scalar %s\n\n", s)
+	}
+
+	names := make([]string, 0, len(r.decls))
+	for n := range r.decls {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	r.ranks.Sort(names)
+	for _, n := range names {
+		r.write(&buf, "%s\n", r.decls[n])
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), r.warnings
+}
+
+func (r *TypeRender) writeObject(name string, d *model.Decl, ctx context) {
+	ctx.decl = name
+
+	if len(d.Fields) == 0 {
+		r.warn(name, "struct without fields is omitted")
+		return
+	}
+	fields := bytes.Buffer{}
+	for _, fld := range d.Fields {
+		n := identifier(fld.Wire)
+		if n != fld.Wire {
+			r.warn(name+"."+fld.Name, fmt.Sprintf("field %q renamed to %s", fld.Wire, n))
+		}
+		r.write(&fields, "    %s: %s\n", n, r.typeOf(ctx, fld.Name, fld.Type))
+	}
+
+	keyword := "type"
+	if ctx.input {
+		keyword = "input"
+	}
+	r.declare(name, keyword+" "+name+" {\n"+fields.String()+"}\n")
+}
+
+// declare adds a declaration. GraphQL has a single namespace, the
+// first declaration of a name wins.
+func (r *TypeRender) declare(name, decl string) {
+	if _, ok := r.decls[name]; ok {
+		r.warn(name, "duplicate type name, declaration omitted")
+		return
+	}
+	r.decls[name] = decl
+	r.ranks.Put(name, r.rank)
+}
+
+// typeOf returns the GraphQL type reference of a type reference.
+// Nullable types are nullable, any other type is non-null.
+func (r *TypeRender) typeOf(ctx context, field string, t *model.Type) string {
+	t = model.Unalias(t, false)
+	typ := ""
+
+	switch t.Kind {
+	case model.Bool:
+		typ = "Boolean!"
+	case model.String, model.Bytes:
+		typ = "String!" // encoding/json encodes []byte as base64 string
+	case model.Int, model.Uint:
+		typ = "Int!"
+		if wide(t) {
+			r.scalars["Int64"] = true
+			typ = "Int64!"
+		}
+	case model.Float:
+		typ = "Float!"
+	case model.Time:
+		r.scalars["Time"] = true
+		typ = "Time!"
+	case model.List:
+		typ = "[" + r.typeOf(ctx, field, t.Elem) + "]!"
+	case model.Ref:
+		typ = r.reference(ctx, field, t.Decl)
+	default:
+		typ = r.custom(ctx, field, reason(t))
+	}
+	if t.Nullable {
+		return strings.TrimSuffix(typ, "!")
+	}
+	return typ
+}
+
+// reference returns the type reference of an object type or enum.
+// Objects of anonymous structs are named after the enclosing object
+// and rendered on reference, those of anonymous structs in aliases
+// are declared on the top level.
+func (r *TypeRender) reference(ctx context, field string, d *model.Decl) string {
+	switch {
+	case d.Kind == model.Enum:
+		return d.Name + "!"
+	case len(d.Fields) == 0:
+		return r.custom(ctx, field, "struct without fields")
+	case d.Parent != nil && d.Parent.Kind == model.Struct:
+		name := ctx.decl + d.Name
+		r.writeObject(name, d, ctx)
+		return name + "!"
+	}
+	return r.objectName(ctx, field, d) + "!"
+}
+
+// wide reports whether an integer exceeds the 32 bits of Int, the
+// Go int is assumed to fit.
+func wide(t *model.Type) bool {
+	if b, ok := t.Origin.(*types.Basic); ok && b.Kind() == types.Int {
+		return false
+	}
+	return t.Bits == 64 || t.Kind == model.Uint && t.Bits == 32
+}
+
+// reason returns why a type is mapped to the custom scalar.
+func reason(t *model.Type) string {
+	switch tt := t.Origin.(type) {
+	case *types.Basic:
+		return tt.Name()
+	case *types.Named:
+		if _, ok := tt.Underlying().(*types.Struct); ok {
+			return "opaque type " + tt.String()
+		}
+	case *types.Struct:
+		return "recursive anonymous struct"
+	}
+	if t.Kind == model.Map {
+		return "map"
+	}
+	return typex.KindOf(t.Origin.Underlying())
+}
+
+// objectName returns the name of a referenced object type. Input
+// types refer to input types, which are scheduled for rendering.
+func (r *TypeRender) objectName(ctx context, field string, d *model.Decl) string {
+	if ctx.input {
+		r.inputs = append(r.inputs, d)
+		return r.inputName(d)
+	}
+	if r.isInput(d) {
+		return strings.TrimSuffix(r.custom(ctx, field, "input type "+d.Name), "!")
+	}
+	return d.Flat()
+}
+
+func (r *TypeRender) custom(ctx context, field, reason string) string {
+	s := r.scalar()
+	r.scalars[s] = true
+	r.warn(ctx.decl+"."+field, reason+" mapped to scalar "+s)
+	return s + "!"
+}
+
+func (r *TypeRender) warn(at, msg string) {
+	r.warnings = append(r.warnings, at+": "+msg)
+}
+
+func (r *TypeRender) writeEnum(d *model.Decl) {
+	buf := bytes.Buffer{}
+	r.write(&buf, "enum %s {\n", d.Name)
+	seen := make(map[string]bool)
+
+	for _, c := range d.Values {
+		if v := identifier(typex.EnumName("", c.Name)); !seen[v] {
+			r.write(&buf, "    %s\n", v)
+			seen[v] = true
+		}
+	}
+	r.write(&buf, "}\n")
+	r.declare(d.Name, buf.String())
+}
+
+func (r *TypeRender) isInput(d *model.Decl) bool {
+	return r.InputFilterFunc != nil && d.Parent == nil && r.InputFilterFunc(d.Origin.String())
+}
+
+func (r *TypeRender) inputName(d *model.Decl) string {
+	if r.isInput(d) {
+		return d.Name
+	}
+	return d.Flat() + "Input"
+}
+
+func (r *TypeRender) scalar() string {
+	if r.Scalar != "" {
+		return r.Scalar
+	}
+	return DefaultScalar
+}
+
+func (TypeRender) write(w *bytes.Buffer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
+
+// Declared reports whether a type of a TypeMap is declared by name in
+// the schema: structs become object types, integer and string types
+// with constants enumerations.
+func Declared(t types.Type) bool {
+	nt, ok := t.(*types.Named)
+	if !ok || nt.Obj().Pkg() == nil || nt.Obj().Pkg().Path() == "time" {
+		return false
+	}
+	switch u := nt.Underlying().(type) {
+	case *types.Struct:
+		return true
+	case *types.Basic:
+		return u.Info()&(types.IsInteger|types.IsString) != 0 && len(typex.Constants(nt, false)) > 0
+	}
+	return false
+}
+
+// identifier returns a valid name of a field or an enum value, limited
+// to ASCII letters, digits and underscores. Names starting with "__"
+// are reserved for introspection.
+func identifier(s string) string {
+	s = (typex.IdentifierPolicy{IsIdentRune: typex.IsASCIIIdent}).Identifier(s)
+	for strings.HasPrefix(s, "__") {
+		s = s[1:]
+	}
+	return s
+}
+
+// Reserved reports whether a type name or a package path segment
+// is a keyword or clashes with a name the rendered schema relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "Boolean", "Float", "ID", "Int", "Int64", "Mutation", "Query",
+		"String", "Subscription", "Time":
+		return true
+	}
+	return strings.HasPrefix(s, "__")
+}
@@ -0,0 +1,90 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package graphql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func inspect(t *testing.T) typex.TypeMap {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p5\.`}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(p5.Order{}).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+	return types
+}
+
+func TestTypeRender_Render(t *testing.T) {
+	tr := TypeRender{}
+	schema, warnings := tr.Render(inspect(t))
+
+	for _, want := range []string{
+		"scalar JSON\n\nscalar Time\n\n",
+		"enum Level {\n    LOW\n    HIGH\n    MAX\n}\n",
+		"enum Status {\n    OPEN\n    CLOSED\n}\n",
+		"type Order {\n" +
+			"    id: String!\n" +
+			"    status: Status!\n" +
+			"    level: Level\n" +
+			"    items: [Item!]!\n" +
+			"    labels: JSON!\n" +
+			"    created: Time!\n" +
+			"    note: String\n" +
+			"    ref: S\n" +
+			"}\n",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("unexpected\n%s", schema)
+		}
+	}
+	if w := warnings[len(warnings)-1]; w != "Order.Labels: map mapped to scalar JSON" {
+		t.Errorf("unexpected %s", w)
+	}
+}
+
+func TestTypeRender_Input(t *testing.T) {
+	tr := TypeRender{
+		InputFilterFunc: typex.CreatePathFilterFunc([]string{`p5\.Order$`}, nil),
+		Scalar:          "Any",
+	}
+	schema, _ := tr.Render(inspect(t))
+
+	for _, want := range []string{
+		"scalar Any\n\n",
+		"input ItemInput {\n    sku: String!\n    quantity: Int!\n}\n",
+		"input Order {\n",
+		"    items: [ItemInput!]!\n",
+		"    ref: SInput\n",
+		"type Item {\n",
+	} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("unexpected\n%s", schema)
+		}
+	}
+	if strings.Contains(schema, "type Order ") {
+		t.Errorf("unexpected\n%s", schema)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	for s, want := range map[string]string{
+		"name":       "name",
+		"-":          "_",
+		"first-name": "first_name",
+		"__typename": "_typename",
+		"größe":      "gr__e",
+		"1st":        "_1st",
+	} {
+		if got := identifier(s); got != want {
+			t.Errorf("unexpected %q: %q", s, got)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type (
+	// IdentifierPolicy is the naming policy of a target language
+	// for type names and the segments of package paths.
+	IdentifierPolicy struct {
+		// Reserved reports whether a name is a keyword or clashes
+		// with a type name the rendered code relies on.
+		Reserved func(string) bool
+
+		// IsIdentRune reports whether a character may appear in an
+		// identifier, default: IsUnicodeIdent.
+		IsIdentRune func(rune) bool
+
+		// Suffix is appended to reserved names, default: "_".
+		Suffix string
+	}
+
+	// Rename is a qualified type name changed by an IdentifierPolicy.
+	Rename struct {
+		From string
+		To   string
+	}
+)
+
+// DefaultSuffix is the suffix appended to reserved names.
+const DefaultSuffix = "_"
+
+// Identifier returns a valid identifier: characters outside of the
+// identifier class of the policy become underscores, a leading digit
+// gets an underscore prefix and a reserved name the suffix.
+func (p IdentifierPolicy) Identifier(s string) string {
+	s = strings.Map(func(c rune) rune {
+		if p.isIdentRune(c) {
+			return c
+		}
+		return '_'
+	}, s)
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "_" + s
+	}
+	if p.Reserved != nil && p.Reserved(s) {
+		s += p.suffix()
+	}
+	return s
+}
+
+// IsUnicodeIdent reports whether a character is a letter, a digit or
+// an underscore, the identifier characters of most target languages.
+func IsUnicodeIdent(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// IsASCIIIdent reports whether a character is an ASCII letter, digit
+// or underscore, the identifier characters of e.g. Protocol Buffers
+// and GraphQL.
+func IsASCIIIdent(c rune) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// Path returns a package path consisting of valid identifiers,
+// dots separating the parts of a segment are kept.
+func (p IdentifierPolicy) Path(path string) string {
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		parts := strings.Split(seg, ".")
+		for j, s := range parts {
+			parts[j] = p.Identifier(s)
+		}
+		segs[i] = strings.Join(parts, ".")
+	}
+	return strings.Join(segs, "/")
+}
+
+// RenameFunc returns a PathReplaceFunc applying the policy to the
+// names of the types of a TypeMap after the path replacement f. The
+// renames are returned in order. Well-known types like time.Time are
+// not declared by any layout and keep their names. Instantiated generic
+// types are named by Instance, which is not reported as a rename.
+func (p IdentifierPolicy) RenameFunc(m TypeMap, f PathReplaceFunc) (PathReplaceFunc, []Rename) {
+	names := Names{PathReplaceFunc: f}
+	renamed := make(map[string]string)
+	renames := make([]Rename, 0)
+
+	for s, t := range m {
+		if isWellKnown(t) {
+			continue // rendered as their serialized counterpart
+		}
+		path, name := names.Split(s)
+		nt, ok := t.(*types.Named)
+		instance := ok && nt.TypeArgs().Len() > 0
+		if instance {
+			name = Instance(nt)
+		}
+		to := p.Identifier(name)
+		if path != "" {
+			to = p.Path(path) + "." + to
+		}
+		if from := names.Replace(s); to != from {
+			renamed[s] = to
+			if !instance {
+				renames = append(renames, Rename{From: from, To: to})
+			}
+		}
+	}
+	sort.Slice(renames, func(i, j int) bool {
+		return renames[i].From < renames[j].From
+	})
+
+	return func(s string) string {
+		if r, ok := renamed[s]; ok {
+			return r
+		}
+		return names.Replace(s)
+	}, renames
+}
+
+// Instance returns a readable name of an instantiated generic type:
+// the name of the generic type followed by the names of its type
+// arguments, e.g. "PairStringNodeList" of Pair[string, []*Node].
+func Instance(t *types.Named) string {
+	var b strings.Builder
+	b.WriteString(t.Obj().Name())
+	args := t.TypeArgs()
+	for i := 0; i < args.Len(); i++ {
+		b.WriteString(argument(args.At(i)))
+	}
+	return b.String()
+}
+
+// argument returns the name of a type argument in an instance name.
+func argument(t types.Type) string {
+	switch tt := Unalias(t).(type) {
+	case *types.Basic:
+		return UpperFirst(tt.Name())
+	case *types.Named:
+		if tt.TypeArgs().Len() > 0 {
+			return Instance(tt)
+		}
+		return UpperFirst(tt.Obj().Name())
+	case *types.Pointer:
+		return argument(tt.Elem())
+	case *types.Slice:
+		return argument(tt.Elem()) + "List"
+	case *types.Array:
+		return argument(tt.Elem()) + "List"
+	case *types.Map:
+		return "Map" + argument(tt.Key()) + argument(tt.Elem())
+	}
+	return "Any"
+}
+
+// UpperFirst returns the string with its first letter in upper case.
+func UpperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func isWellKnown(t types.Type) bool {
+	nt, ok := t.(*types.Named)
+	if !ok || nt.Obj().Pkg() == nil || nt.Obj().Pkg().Path() != "time" {
+		return false
+	}
+	return nt.Obj().Name() == "Time" || nt.Obj().Name() == "Duration"
+}
+
+func (p IdentifierPolicy) isIdentRune(c rune) bool {
+	if p.IsIdentRune != nil {
+		return p.IsIdentRune(c)
+	}
+	return IsUnicodeIdent(c)
+}
+
+func (p IdentifierPolicy) suffix() string {
+	if p.Suffix != "" {
+		return p.Suffix
+	}
+	return DefaultSuffix
+}
@@ -0,0 +1,94 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func TestIdentifierPolicy_Identifier(t *testing.T) {
+	p := IdentifierPolicy{Reserved: func(s string) bool { return s == "delete" || s == "Object" }}
+
+	for s, want := range map[string]string{
+		"Order":  "Order",
+		"delete": "delete_",
+		"Object": "Object_",
+		"go-kit": "go_kit",
+		"3rd":    "_3rd",
+		"":       "_",
+		"Größe":  "Größe",
+	} {
+		if got := p.Identifier(s); got != want {
+			t.Errorf("unexpected %q: %q", s, got)
+		}
+	}
+	ascii := IdentifierPolicy{IsIdentRune: IsASCIIIdent}
+	for s, want := range map[string]string{
+		"Order": "Order",
+		"Größe": "Gr__e",
+		"-":     "_",
+	} {
+		if got := ascii.Identifier(s); got != want {
+			t.Errorf("unexpected %q: %q", s, got)
+		}
+	}
+	p.Suffix = "X"
+	if got := p.Path("github.com/a/delete"); got != "github.com/a/deleteX" {
+		t.Errorf("unexpected %q", got)
+	}
+}
+
+func TestIdentifierPolicy_RenameFunc(t *testing.T) {
+	p := IdentifierPolicy{Reserved: func(s string) bool { return s == "default" || s == "Object" }}
+	m := TypeMap{"a/default.Object": nil, "a/default.T": nil, "a/b.T": nil}
+
+	f, renames := p.RenameFunc(m, nil)
+	want := []Rename{
+		{From: "a/default.Object", To: "a/default_.Object_"},
+		{From: "a/default.T", To: "a/default_.T"},
+	}
+	if !reflect.DeepEqual(renames, want) {
+		t.Errorf("unexpected %v", renames)
+	}
+	if s := f("a/default.T"); s != "a/default_.T" {
+		t.Errorf("unexpected %q", s)
+	}
+	if s := f("a/b.T"); s != "a/b.T" {
+		t.Errorf("unexpected %q", s)
+	}
+}
+
+func TestInstance(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", `package p
+type Node struct{}
+type Pair[K, V any] struct{}
+var (
+	a Pair[string, *Node]
+	b Pair[[]Node, map[string]int]
+	c Pair[Pair[int, bool], any]
+)`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for v, want := range map[string]string{
+		"a": "PairStringNode",
+		"b": "PairNodeListMapStringInt",
+		"c": "PairPairIntBoolAny",
+	} {
+		nt := pkg.Scope().Lookup(v).Type().(*types.Named)
+		if got := Instance(nt); got != want {
+			t.Errorf("unexpected %s: %q", v, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/types"
+	"sort"
+)
+
+type (
+	// Implementation relates a concrete type to an interface it
+	// implements. Pointer denotes that only the pointer to the
+	// concrete type implements the interface.
+	Implementation struct {
+		Type      *types.Named
+		Interface *types.Named
+		Pointer   bool
+	}
+)
+
+// Implementations relates the interfaces in the TypeMap to the concrete
+// named types implementing them, and the concrete types in the TypeMap
+// to the interfaces they implement. The candidates are taken from the
+// TypeMap and from the packages inspected before. Interfaces without
+// methods are omitted, the result is ordered by type name.
+func (p *Packagist) Implementations(m TypeMap) []Implementation {
+	ifaces := make(map[string]*types.Named)
+	concrete := make(map[string]*types.Named)
+
+	add := func(t *types.Named) {
+		if t.TypeParams().Len() > 0 {
+			return // uninstantiated generic type
+		}
+		if it, ok := t.Underlying().(*types.Interface); ok {
+			if it.NumMethods() > 0 {
+				ifaces[t.String()] = t
+			}
+		} else {
+			concrete[t.String()] = t
+		}
+	}
+	for _, t := range p.named {
+		add(t)
+	}
+	for _, t := range m {
+		if nt, ok := t.(*types.Named); ok {
+			add(nt)
+		}
+	}
+
+	impls := make([]Implementation, 0)
+	for _, it := range ifaces {
+		iface := it.Underlying().(*types.Interface)
+
+		for _, ct := range concrete {
+			_, inMap := m[ct.String()]
+			if _, ok := m[it.String()]; !ok && !inMap {
+				continue
+			}
+			switch {
+			case types.Implements(ct, iface):
+				impls = append(impls, Implementation{ct, it, false})
+			case types.Implements(types.NewPointer(ct), iface):
+				impls = append(impls, Implementation{ct, it, true})
+			}
+		}
+	}
+	sort.Slice(impls, func(i, j int) bool {
+		a, b := impls[i], impls[j]
+		if a.Interface.String() != b.Interface.String() {
+			return a.Interface.String() < b.Interface.String()
+		}
+		return a.Type.String() < b.Type.String()
+	})
+	return impls
+}
@@ -0,0 +1,34 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dtgorski/typex/internal/testdata/p4"
+)
+
+func TestPackagist_Implementations(t *testing.T) {
+	pkgPath := reflect.TypeOf(p4.A{}).PkgPath()
+
+	pac := Packagist{
+		PathFilterFunc: CreatePathFilterFunc([]string{`p4\.[AS]$`}, nil),
+	}
+	types, err := pac.Inspect(pkgPath)
+	if err != nil {
+		t.Error("unexpected")
+		return
+	}
+
+	got := make([]string, 0)
+	for _, impl := range pac.Implementations(types) {
+		s := fmt.Sprintf("%s:%s:%v", impl.Interface.Obj().Name(), impl.Type.Obj().Name(), impl.Pointer)
+		got = append(got, s)
+	}
+	if s := strings.Join(got, " "); s != "S:A:false T:A:true" {
+		t.Errorf("unexpected %s", s)
+	}
+}
@@ -0,0 +1,313 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package java
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
+)
+
+type (
+	// TypeRender renders Go types as Java records serialized with
+	// Jackson, one file per top level declaration.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+		TagKey            string
+		Ranking           typex.Ranking
+		SortFields        bool
+	}
+)
+
+// Render converts a TypeMap to Java files. Structs are rendered as
+// records, anonymous structs as records nested in the enclosing one,
+// enumerations as enums carrying their value. Aliases have no
+// counterpart, references to them resolve to the aliased type.
+// Unsigned integers are widened to the next type holding all their
+// values. Nullable fields tagged "omitempty" are omitted when null.
+func (r *TypeRender) Render(m typex.TypeMap) []typex.File {
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		Ranking:           r.Ranking,
+		SortFields:        r.SortFields,
+	}
+	mod := b.Build(m)
+	files := make([]typex.File, 0)
+
+	for _, d := range mod.Decls {
+		switch d.Kind {
+		case model.Struct:
+			files = append(files, r.file(d, r.record))
+		case model.Enum:
+			files = append(files, r.file(d, r.enum))
+		case model.Alias:
+			for _, n := range d.Nested {
+				files = append(files, r.file(n, r.record))
+			}
+		}
+	}
+	return files
+}
+
+func (r *TypeRender) file(d *model.Decl, decl func(map[string]bool, *model.Decl, string) string) typex.File {
+	imports := make(map[string]bool)
+	body := decl(imports, d, "")
+
+	buf := bytes.Buffer{}
+	pkg := pkgName(d.Path)
+	if pkg != "" {
+		r.write(&buf, "package %s;\n\n", pkg)
+	}
+	list := make([]string, 0, len(imports))
+	for i := range imports {
+		list = append(list, i)
+	}
+	sort.Strings(list)
+	for _, i := range list {
+		r.write(&buf, "import %s;\n", i)
+	}
+	if len(list) > 0 {
+		r.write(&buf, "\n")
+	}
+	r.write(&buf, "%s", body)
+
+	path := strings.ReplaceAll(pkg, ".", "/")
+	if path != "" {
+		path += "/"
+	}
+	return typex.File{Path: path + declName(d) + ".java", Content: buf.String()}
+}
+
+func (r *TypeRender) record(imports map[string]bool, d *model.Decl, pad string) string {
+	buf := bytes.Buffer{}
+	r.write(&buf, "%spublic record %s(", pad, declName(d))
+
+	for i, fld := range d.Fields {
+		imports["com.fasterxml.jackson.annotation.JsonProperty"] = true
+		r.write(&buf, "\n%s    @JsonProperty(%s) ", pad, strconv.Quote(fld.Wire))
+
+		if fld.Optional {
+			imports["com.fasterxml.jackson.annotation.JsonInclude"] = true
+			r.write(&buf, "@JsonInclude(JsonInclude.Include.NON_NULL) ")
+		}
+		typ := r.typeOf(imports, d, fld.Type, fld.Nullable())
+		r.write(&buf, "%s %s", typ, identifier(typex.LowerCamelCase(fld.Name)))
+
+		if i < len(d.Fields)-1 {
+			r.write(&buf, ",")
+		} else {
+			r.write(&buf, "\n%s", pad)
+		}
+	}
+	r.write(&buf, ") {\n")
+
+	for i, n := range d.Nested {
+		if i > 0 {
+			r.write(&buf, "\n")
+		}
+		r.write(&buf, "%s", r.record(imports, n, pad+"    "))
+	}
+	r.write(&buf, "%s}\n", pad)
+	return buf.String()
+}
+
+func (r *TypeRender) enum(imports map[string]bool, d *model.Decl, _ string) string {
+	imports["com.fasterxml.jackson.annotation.JsonValue"] = true
+	name := declName(d)
+
+	typ, suffix := "String", ""
+	switch {
+	case d.Type.Kind == model.Int && d.Type.Bits <= 32, d.Type.Kind == model.Uint && d.Type.Bits <= 16:
+		typ = "int"
+	case d.Type.Kind == model.Int, d.Type.Kind == model.Uint:
+		typ, suffix = "long", "L"
+	}
+
+	consts := make([]string, 0, len(d.Values))
+	seen := make(map[string]bool)
+	for _, v := range d.Values {
+		n := identifier(typex.EnumName("", v.Name))
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+
+		val := v.Value.ExactString() + suffix
+		if v.Value.Kind() == constant.String {
+			val = strconv.Quote(constant.StringVal(v.Value))
+		}
+		consts = append(consts, "    "+n+"("+val+")")
+	}
+
+	buf := bytes.Buffer{}
+	r.write(&buf, "public enum %s {\n%s;\n\n", name, strings.Join(consts, ",\n"))
+	r.write(&buf, "    private final %s value;\n\n", typ)
+	r.write(&buf, "    %s(%s value) {\n        this.value = value;\n    }\n\n", name, typ)
+	r.write(&buf, "    @JsonValue\n    public %s value() {\n        return value;\n    }\n}\n", typ)
+	return buf.String()
+}
+
+// typeOf returns the Java type of a type reference. Nullable types
+// are boxed. Types without serializable counterpart are rendered as
+// JSON nodes.
+func (r *TypeRender) typeOf(imports map[string]bool, ctx *model.Decl, t *model.Type, boxed bool) string {
+	t = model.Unalias(t, false)
+	boxed = boxed || t.Nullable
+
+	switch t.Kind {
+	case model.Time:
+		imports["java.time.OffsetDateTime"] = true
+		return "OffsetDateTime"
+	case model.List:
+		imports["java.util.List"] = true
+		return "List<" + r.typeOf(imports, ctx, t.Elem, true) + ">"
+	case model.Map:
+		imports["java.util.Map"] = true
+		return "Map<" + r.keyOf(imports, t.Key) + ", " + r.typeOf(imports, ctx, t.Elem, true) + ">"
+	case model.Ref:
+		return reference(ctx, t.Decl)
+	case model.Any:
+		imports["com.fasterxml.jackson.databind.JsonNode"] = true
+		return "JsonNode"
+	}
+	return r.basic(imports, t, boxed)
+}
+
+// keyOf returns the type of a map key, enumerations are keyed by
+// their values, like encoding/json does.
+func (r *TypeRender) keyOf(imports map[string]bool, t *model.Type) string {
+	t = model.Unalias(t, true)
+	if t.Kind == model.Int && !t.Nullable || t.Kind == model.Uint && !t.Nullable {
+		return r.basic(imports, t, true)
+	}
+	return "String"
+}
+
+func (r *TypeRender) basic(imports map[string]bool, t *model.Type, boxed bool) string {
+	prim, box := "", ""
+
+	switch t.Kind {
+	case model.Bool:
+		prim, box = "boolean", "Boolean"
+	case model.String:
+		return "String"
+	case model.Bytes:
+		return "byte[]" // base64 encoded, like encoding/json
+	case model.Float:
+		prim, box = "double", "Double"
+		if t.Bits == 32 {
+			prim, box = "float", "Float"
+		}
+	case model.Int:
+		prim, box = widths(t.Bits)
+	case model.Uint:
+		if t.Bits == 64 {
+			imports["java.math.BigInteger"] = true
+			return "BigInteger"
+		}
+		prim, box = widths(t.Bits * 2)
+	}
+	if boxed {
+		return box
+	}
+	return prim
+}
+
+func widths(bits int) (string, string) {
+	switch bits {
+	case 8:
+		return "byte", "Byte"
+	case 16:
+		return "short", "Short"
+	case 32:
+		return "int", "Integer"
+	}
+	return "long", "Long"
+}
+
+// reference returns the name of a declaration, qualified by its
+// package when declared in another package.
+func reference(ctx *model.Decl, d *model.Decl) string {
+	parts := strings.Split(d.Qualified("."), ".")
+	for i, s := range parts {
+		parts[i] = identifier(s)
+	}
+	name := strings.Join(parts, ".")
+
+	if d.Path == ctx.Path || d.Path == "" {
+		return name
+	}
+	return pkgName(d.Path) + "." + name
+}
+
+// declName returns the name of a declaration. Records of anonymous
+// structs in aliases are declared on the top level.
+func declName(d *model.Decl) string {
+	if d.Parent != nil && d.Parent.Kind == model.Struct {
+		return identifier(d.Name)
+	}
+	return identifier(d.Qualified(""))
+}
+
+func (TypeRender) write(w *bytes.Buffer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
+
+// pkgName returns the package name of a path consisting of valid
+// identifiers.
+func pkgName(path string) string {
+	parts := make([]string, 0)
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			parts = append(parts, identifier(strings.Map(func(c rune) rune {
+				if c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+					return c
+				}
+				return '_'
+			}, s)))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// identifier returns a valid Java identifier, keywords get an
+// underscore suffix.
+func identifier(s string) string {
+	switch s {
+	case "abstract", "assert", "boolean", "break", "byte", "case", "catch",
+		"char", "class", "const", "continue", "default", "do", "double",
+		"else", "enum", "extends", "false", "final", "finally", "float",
+		"for", "goto", "if", "implements", "import", "instanceof", "int",
+		"interface", "long", "native", "new", "null", "package", "private",
+		"protected", "public", "return", "short", "static", "strictfp",
+		"super", "switch", "synchronized", "this", "throw", "throws",
+		"transient", "true", "try", "void", "volatile", "while", "_":
+		return s + "_"
+	}
+	if s != "" && !unicode.IsLetter([]rune(s)[0]) && s[0] != '_' {
+		return "_" + s
+	}
+	return s
+}
+
+// Reserved reports whether a type name or a package path segment
+// is a keyword or clashes with a name the rendered Java code relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "BigInteger", "Boolean", "Byte", "Character", "Double", "Float",
+		"Integer", "JsonInclude", "JsonNode", "JsonProperty", "JsonValue",
+		"List", "Long", "Map", "Object", "OffsetDateTime", "Short", "String":
+		return true
+	}
+	return identifier(s) != s
+}
@@ -0,0 +1,75 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package java
+
+import (
+	"reflect"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func TestTypeRender_Render(t *testing.T) {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p5\.`}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(p5.Order{}).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+	tr := TypeRender{PathReplaceFunc: typex.CreatePathReplaceFunc([]string{".*/testdata:"})}
+
+	want := map[string]string{
+		"p5/Order.java": `package p5;
+
+import com.fasterxml.jackson.annotation.JsonInclude;
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.List;
+import java.util.Map;
+
+public record Order(
+    @JsonProperty("id") String id,
+    @JsonProperty("status") Status status,
+    @JsonProperty("level") @JsonInclude(JsonInclude.Include.NON_NULL) Level level,
+    @JsonProperty("items") List<Item> items,
+    @JsonProperty("labels") Map<String, String> labels,
+    @JsonProperty("created") OffsetDateTime created,
+    @JsonProperty("note") @JsonInclude(JsonInclude.Include.NON_NULL) String note,
+    @JsonProperty("ref") p2.S ref
+) {
+}
+`,
+		"p5/Level.java": `package p5;
+
+import com.fasterxml.jackson.annotation.JsonValue;
+
+public enum Level {
+    LOW(0),
+    HIGH(1),
+    MAX(1);
+
+    private final int value;
+
+    Level(int value) {
+        this.value = value;
+    }
+
+    @JsonValue
+    public int value() {
+        return value;
+    }
+}
+`,
+	}
+	files := tr.Render(types)
+	if len(files) != 6 {
+		t.Fatalf("unexpected %d", len(files))
+	}
+	for _, f := range files {
+		if s, ok := want[f.Path]; ok && s != f.Content {
+			t.Errorf("unexpected\n%s", f.Content)
+		}
+	}
+}
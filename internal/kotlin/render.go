@@ -0,0 +1,332 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package kotlin
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
+)
+
+type (
+	// TypeRender renders Go types as Kotlin data classes annotated
+	// for kotlinx.serialization, one file per package.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+		TagKey            string
+		Ranking           typex.Ranking
+		SortFields        bool
+	}
+
+	file struct {
+		pkg     string
+		imports map[string]bool
+	}
+)
+
+// Render converts a TypeMap to Kotlin files. Structs are rendered as
+// data classes, anonymous structs as classes nested in the enclosing
+// class. Integer enumerations are rendered as inline value classes,
+// string enumerations as enum classes, other named types as type
+// aliases. Pointers and fields tagged "omitempty" are nullable and
+// default to null.
+func (r *TypeRender) Render(m typex.TypeMap) []typex.File {
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		Ranking:           r.Ranking,
+		SortFields:        r.SortFields,
+	}
+	mod := b.Build(m)
+	files := make([]typex.File, 0)
+
+	for _, p := range mod.Packages() {
+		f := &file{pkg: pkgName(p), imports: make(map[string]bool)}
+		decls := make([]string, 0)
+
+		for _, d := range mod.Package(p) {
+			switch d.Kind {
+			case model.Struct:
+				decls = append(decls, r.class(f, d))
+			case model.Enum:
+				decls = append(decls, r.enum(f, d))
+			case model.Alias:
+				nested := make([]string, 0, len(d.Nested)+1)
+				for _, n := range d.Nested {
+					nested = append(nested, r.class(f, n))
+				}
+				nested = append(nested, "typealias "+d.Name+" = "+r.typeOf(f, d.Type)+"\n")
+				decls = append(decls, strings.Join(nested, "\n"))
+			}
+		}
+		path := strings.ReplaceAll(strings.ReplaceAll(f.pkg, "`", ""), ".", "/")
+		if path != "" {
+			path += "/"
+		}
+		files = append(files, typex.File{Path: path + "Types.kt", Content: r.source(f, decls)})
+	}
+	return files
+}
+
+func (r *TypeRender) source(f *file, decls []string) string {
+	buf := bytes.Buffer{}
+	if f.pkg != "" {
+		r.write(&buf, "package %s\n\n", f.pkg)
+	}
+	if len(f.imports) > 0 {
+		for _, i := range sortedKeys(f.imports) {
+			r.write(&buf, "import %s\n", i)
+		}
+		r.write(&buf, "\n")
+	}
+	r.write(&buf, "%s", strings.Join(decls, "\n"))
+	return buf.String()
+}
+
+// class returns the declaration of a struct and the declarations
+// of its anonymous structs, nested in the class.
+func (r *TypeRender) class(f *file, d *model.Decl) string {
+	fields := bytes.Buffer{}
+	r.writeFields(f, &fields, d)
+
+	f.imports["kotlinx.serialization.Serializable"] = true
+	buf := bytes.Buffer{}
+	r.write(&buf, "@Serializable\n")
+
+	if fields.Len() == 0 {
+		r.write(&buf, "class %s", declName(d)) // data classes require properties
+	} else {
+		r.write(&buf, "data class %s(\n%s)", declName(d), fields.String())
+	}
+	if len(d.Nested) > 0 {
+		nested := make([]string, 0, len(d.Nested))
+		for _, n := range d.Nested {
+			nested = append(nested, r.class(f, n))
+		}
+		r.write(&buf, " {\n%s}", indent(strings.Join(nested, "\n")))
+	}
+	r.write(&buf, "\n")
+	return buf.String()
+}
+
+func (r *TypeRender) writeFields(f *file, w *bytes.Buffer, d *model.Decl) {
+	for _, fld := range d.Fields {
+		typ := r.typeOf(f, fld.Type)
+		if fld.Optional || fld.Type.Nullable {
+			typ = strings.TrimSuffix(typ, "?") + "? = null"
+		}
+
+		name := identifier(typex.LowerCamelCase(fld.Name))
+		r.write(w, "    ")
+		if strings.Trim(name, "`") != fld.Wire {
+			f.imports["kotlinx.serialization.SerialName"] = true
+			r.write(w, "@SerialName(%s) ", quote(fld.Wire))
+		}
+		r.write(w, "val %s: %s,\n", name, typ)
+	}
+}
+
+// typeOf returns the Kotlin type of a type reference. Types without
+// a serializable counterpart are rendered as JSON elements.
+func (r *TypeRender) typeOf(f *file, t *model.Type) string {
+	typ := ""
+
+	switch t.Kind {
+	case model.Bool, model.Int, model.Uint, model.Float, model.String:
+		typ = basic(t)
+	case model.Bytes:
+		typ = "String" // encoding/json encodes []byte as base64 string
+	case model.Time:
+		f.imports["kotlinx.datetime.Instant"] = true
+		typ = "Instant"
+	case model.List:
+		typ = "List<" + r.typeOf(f, t.Elem) + ">"
+	case model.Map:
+		typ = "Map<" + r.keyOf(f, t.Key) + ", " + r.typeOf(f, t.Elem) + ">"
+	case model.Ref:
+		typ = reference(f, t.Decl)
+	default:
+		f.imports["kotlinx.serialization.json.JsonElement"] = true
+		typ = "JsonElement"
+	}
+	if t.Nullable {
+		return typ + "?"
+	}
+	return typ
+}
+
+// keyOf returns the type of a map key, kotlinx.serialization
+// supports primitive map keys only.
+func (r *TypeRender) keyOf(f *file, t *model.Type) string {
+	switch u := model.Unalias(t, true); {
+	case u.Nullable:
+	case u.Kind == model.Int, u.Kind == model.Uint, u.Kind == model.String:
+		return r.typeOf(f, t)
+	}
+	return "String"
+}
+
+func basic(t *model.Type) string {
+	switch t.Kind {
+	case model.Bool:
+		return "Boolean"
+	case model.String:
+		return "String"
+	case model.Int:
+		return map[int]string{8: "Byte", 16: "Short", 32: "Int", 64: "Long"}[t.Bits]
+	case model.Uint:
+		return map[int]string{8: "UByte", 16: "UShort", 32: "UInt", 64: "ULong"}[t.Bits]
+	case model.Float:
+		if t.Bits == 32 {
+			return "Float"
+		}
+		return "Double"
+	}
+	return ""
+}
+
+// reference returns the name of a declaration, qualified by its
+// package when declared in another package.
+func reference(f *file, d *model.Decl) string {
+	pkg, name := pkgName(d.Path), d.Qualified(".")
+	if pkg == f.pkg || pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// declName returns the name of a declaration. Classes of anonymous
+// structs in type aliases are declared on the top level.
+func declName(d *model.Decl) string {
+	if d.Parent != nil && d.Parent.Kind == model.Struct {
+		return d.Name
+	}
+	return d.Qualified("")
+}
+
+func (r *TypeRender) enum(f *file, d *model.Decl) string {
+	buf := bytes.Buffer{}
+	seen := make(map[string]bool)
+
+	f.imports["kotlinx.serialization.Serializable"] = true
+	r.write(&buf, "@Serializable\n")
+
+	if d.Type.Kind == model.String {
+		f.imports["kotlinx.serialization.SerialName"] = true
+		r.write(&buf, "enum class %s {\n", d.Name)
+		for _, c := range d.Values {
+			n, v := identifier(typex.EnumName("", c.Name)), constant.StringVal(c.Value)
+			if seen[n] || seen[v] {
+				continue // kotlinx.serialization rejects duplicate serial names
+			}
+			seen[n], seen[v] = true, true
+			r.write(&buf, "    @SerialName(%s)\n    %s,\n", quote(v), n)
+		}
+		r.write(&buf, "}\n")
+		return buf.String()
+	}
+
+	// Integer enums are encoded as numbers, which enum classes
+	// are not, an inline value class provides the constants.
+	f.imports["kotlin.jvm.JvmInline"] = true
+	r.write(&buf, "@JvmInline\nvalue class %s(val value: %s) {\n", d.Name, basic(d.Type))
+	r.write(&buf, "    companion object {\n")
+
+	suffix := ""
+	if d.Type.Kind == model.Uint {
+		suffix = "u"
+	}
+	for _, c := range d.Values {
+		n := identifier(typex.EnumName("", c.Name))
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		r.write(&buf, "        val %s = %s(%s%s)\n", n, d.Name, c.Value.ExactString(), suffix)
+	}
+	r.write(&buf, "    }\n}\n")
+	return buf.String()
+}
+
+func (TypeRender) write(w *bytes.Buffer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
+
+// pkgName returns the package name of a path consisting of valid
+// identifiers.
+func pkgName(path string) string {
+	parts := make([]string, 0)
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			parts = append(parts, identifier(strings.Map(func(c rune) rune {
+				if c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+					return c
+				}
+				return '_'
+			}, s)))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// identifier returns a valid Kotlin identifier, keywords are
+// escaped with backticks.
+func identifier(s string) string {
+	switch s {
+	case "as", "break", "class", "continue", "do", "else", "false", "for",
+		"fun", "if", "in", "interface", "is", "null", "object", "package",
+		"return", "super", "this", "throw", "true", "try", "typealias",
+		"typeof", "val", "var", "when", "while":
+		return "`" + s + "`"
+	}
+	if s != "" && !unicode.IsLetter([]rune(s)[0]) && s[0] != '_' {
+		return "_" + s
+	}
+	return s
+}
+
+// quote returns a Kotlin string literal, which interpolates "$".
+func quote(s string) string {
+	return strings.ReplaceAll(strconv.Quote(s), "$", `\$`)
+}
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = "    " + l
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Reserved reports whether a type name or a package path segment
+// is a keyword or clashes with a name the rendered Kotlin code relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "Any", "Boolean", "Byte", "Double", "Float", "Int", "Instant",
+		"JsonElement", "JvmInline", "List", "Long", "Map", "Nothing",
+		"SerialName", "Serializable", "Short", "String", "UByte", "UInt",
+		"ULong", "UShort", "Unit", "kotlin", "kotlinx":
+		return true
+	}
+	return identifier(s) != s
+}
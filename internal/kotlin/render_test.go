@@ -0,0 +1,84 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package kotlin
+
+import (
+	"reflect"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func TestTypeRender_Render(t *testing.T) {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p5\.`}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(p5.Order{}).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+	tr := TypeRender{PathReplaceFunc: typex.CreatePathReplaceFunc([]string{".*/testdata:"})}
+
+	want := `package p5
+
+import kotlin.jvm.JvmInline
+import kotlinx.datetime.Instant
+import kotlinx.serialization.Serializable
+
+@Serializable
+data class Item(
+    val sku: String,
+    val quantity: Int,
+)
+
+@Serializable
+@JvmInline
+value class Level(val value: UByte) {
+    companion object {
+        val LOW = Level(0u)
+        val HIGH = Level(1u)
+        val MAX = Level(1u)
+    }
+}
+
+@Serializable
+data class Order(
+    val id: String,
+    val status: Status,
+    val level: Level? = null,
+    val items: List<Item>,
+    val labels: Map<String, String>,
+    val created: Instant,
+    val note: String? = null,
+    val ref: p2.S? = null,
+)
+
+@Serializable
+@JvmInline
+value class Status(val value: Long) {
+    companion object {
+        val OPEN = Status(1)
+        val CLOSED = Status(2)
+    }
+}
+`
+	for _, f := range tr.Render(types) {
+		if f.Path == "p5/Types.kt" && f.Content != want {
+			t.Errorf("unexpected\n%s", f.Content)
+		}
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	for in, want := range map[string]string{
+		"in":   "`in`",
+		"is":   "`is`",
+		"1":    "_1",
+		"name": "name",
+	} {
+		if got := identifier(in); got != want {
+			t.Errorf("unexpected %q, expected %q", got, want)
+		}
+	}
+}
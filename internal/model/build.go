@@ -0,0 +1,431 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package model
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+
+	typex "github.com/dtgorski/typex/internal"
+)
+
+type (
+	// Builder builds a Model from a TypeMap.
+	Builder struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		DocFunc           typex.DocFunc
+		IncludeUnexported bool
+		TagKey            string
+		Ranking           typex.Ranking
+		SortFields        bool
+
+		decls map[*types.Named]*Decl
+	}
+
+	// candidate is a field found at a depth of embedding, subject
+	// to the dominance rules of encoding/json.
+	candidate struct {
+		field  *Field
+		depth  int
+		tagged bool
+	}
+
+	scope struct {
+		decl  *Decl
+		seen  []*types.Struct
+		named []*types.Named
+	}
+)
+
+// Build converts a TypeMap to a Model. Named structs become Struct
+// declarations, integer and string types with constants declared in
+// their package become Enum declarations, any other named type an
+// Alias declaration. Interfaces, channels and functions, as well as
+// well-known types like time.Time, have no declaration.
+func (b *Builder) Build(m typex.TypeMap) *Model {
+	b.decls = make(map[*types.Named]*Decl)
+	model := &Model{Decls: make([]*Decl, 0)}
+
+	for _, t := range m {
+		nt, ok := t.(*types.Named)
+		if !ok || nt.Obj().Pkg() == nil || builtin(nt) != nil || !isTranslatable(nt) {
+			continue
+		}
+		path, name := b.pathAndName(nt)
+		d := &Decl{Path: path, Name: name, Doc: b.doc(nt.Obj()), Origin: nt}
+		b.decls[nt] = d
+		model.Decls = append(model.Decls, d)
+	}
+	sort.Slice(model.Decls, func(i, j int) bool {
+		a, c := model.Decls[i], model.Decls[j]
+		if a.Path != c.Path {
+			return a.Path < c.Path
+		}
+		if a.Name != c.Name {
+			return a.Name < c.Name
+		}
+		return a.Origin.String() < c.Origin.String()
+	})
+	b.rank(model.Decls)
+
+	for _, d := range model.Decls {
+		nt := d.Origin.(*types.Named)
+
+		switch tt := nt.Underlying().(type) {
+		case *types.Struct:
+			d.Kind = Struct
+			d.Fields = b.fields(&scope{decl: d, seen: []*types.Struct{tt}}, tt)
+		default:
+			if consts := b.enumValues(nt); len(consts) > 0 {
+				d.Kind = Enum
+				d.Type = b.typeOf(&scope{decl: d}, "", tt)
+				d.Values = b.values(nt, consts)
+				continue
+			}
+			d.Kind = Alias
+			d.Type = b.typeOf(&scope{decl: d}, "Item", tt)
+		}
+	}
+	for _, d := range model.Decls {
+		if d.Kind == Alias && reaches(d.Type, d, nil) {
+			d.Type = &Type{Kind: Any, Origin: d.Origin.Underlying()}
+		}
+	}
+	return model
+}
+
+func (b *Builder) fields(sc *scope, st *types.Struct) []*Field {
+	fields := dominant(b.candidates(sc, st, 0))
+	if b.SortFields {
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].Name < fields[j].Name
+		})
+	}
+	return fields
+}
+
+// rank orders the declarations by the Ranking, if any. The packages
+// follow the first of their declarations.
+func (b *Builder) rank(decls []*Decl) {
+	if b.Ranking == nil {
+		return
+	}
+	first := make(map[string]int)
+	for _, d := range decls {
+		i := b.Ranking.Of(d.Origin.String())
+		if j, ok := first[d.Path]; !ok || i < j {
+			first[d.Path] = i
+		}
+	}
+	sort.SliceStable(decls, func(i, j int) bool {
+		a, c := decls[i], decls[j]
+		if first[a.Path] != first[c.Path] {
+			return first[a.Path] < first[c.Path]
+		}
+		return b.Ranking.Of(a.Origin.String()) < b.Ranking.Of(c.Origin.String())
+	})
+}
+
+func (b *Builder) candidates(sc *scope, st *types.Struct, depth int) []candidate {
+	list := make([]candidate, 0)
+
+	for i, n := 0, st.NumFields(); i < n; i++ {
+		fld := st.Field(i)
+		name, _ := (typex.StructTag)(st.Tag(i)).Get(b.tagKey())
+		tag := (typex.StructTag)(st.Tag(i)).Field(b.tagKey(), fld.Name())
+
+		if tag.Skip {
+			continue
+		}
+		if s, ok := b.inlined(sc, fld, tag, name != ""); ok {
+			sc.seen = append(sc.seen, s)
+			list = append(list, b.candidates(sc, s, depth+1)...)
+			continue
+		}
+		if !b.names().IsExported(fld.Name()) {
+			continue
+		}
+		typ := b.typeOf(sc, fld.Name(), fld.Type())
+		if tag.Quoted {
+			typ = &Type{Kind: String, Nullable: typ.Nullable, Origin: typ.Origin}
+		}
+		f := &Field{
+			Name:     fld.Name(),
+			Wire:     tag.Name,
+			Type:     typ,
+			Optional: tag.Optional,
+			Doc:      b.doc(fld),
+			Origin:   fld,
+		}
+		list = append(list, candidate{field: f, depth: depth, tagged: name != ""})
+	}
+	return list
+}
+
+// dominant returns the fields surviving name conflicts like with
+// encoding/json: the least nested field wins, on equal nesting the
+// only tagged one, otherwise all conflicting fields are dropped.
+func dominant(list []candidate) []*Field {
+	byWire := make(map[string][]candidate)
+	for _, c := range list {
+		byWire[c.field.Wire] = append(byWire[c.field.Wire], c)
+	}
+	fields := make([]*Field, 0, len(list))
+	for _, c := range list {
+		if winner(byWire[c.field.Wire]) == c.field {
+			fields = append(fields, c.field)
+		}
+	}
+	return fields
+}
+
+func winner(group []candidate) *Field {
+	depth := group[0].depth
+	for _, c := range group {
+		if c.depth < depth {
+			depth = c.depth
+		}
+	}
+	var win, tagged *Field
+	count, tags := 0, 0
+
+	for _, c := range group {
+		if c.depth != depth {
+			continue
+		}
+		count, win = count+1, c.field
+		if c.tagged {
+			tags, tagged = tags+1, c.field
+		}
+	}
+	switch {
+	case count == 1:
+		return win
+	case tags == 1:
+		return tagged
+	}
+	return nil
+}
+
+// inlined returns the struct whose fields are flattened into the
+// enclosing struct: the struct of a field tagged inline, or of an
+// untagged embedded field when the library flattens those, like
+// encoding/json does.
+func (b *Builder) inlined(sc *scope, f *types.Var, tag typex.FieldTag, named bool) (*types.Struct, bool) {
+	if !tag.Inline && (!f.Embedded() || named || !flattens(b.tagKey())) {
+		return nil, false
+	}
+	t := f.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	for _, s := range sc.seen {
+		if ok && s == st {
+			return nil, false
+		}
+	}
+	return st, ok
+}
+
+// flattens reports whether the library bound to a tag key flattens
+// embedded structs without being told so by an "inline" option.
+func flattens(key string) bool {
+	switch key {
+	case "yaml", "bson", "mapstructure":
+		return false
+	}
+	return true
+}
+
+func (b *Builder) typeOf(sc *scope, field string, t types.Type) *Type {
+	switch tt := typex.Unalias(t).(type) {
+	case *types.Array:
+		return b.listOf(sc, field, tt, tt.Elem())
+
+	case *types.Basic:
+		return basic(tt)
+
+	case *types.Map:
+		return &Type{Kind: Map, Key: b.typeOf(sc, field, tt.Key()), Elem: b.typeOf(sc, field, tt.Elem()), Origin: t}
+
+	case *types.Named:
+		if typ := builtin(tt); typ != nil {
+			return typ
+		}
+		if d, ok := b.decls[tt]; ok {
+			return &Type{Kind: Ref, Decl: d, Origin: t}
+		}
+		if _, ok := tt.Underlying().(*types.Struct); ok {
+			return &Type{Kind: Any, Origin: t} // opaque, beyond the TypeMap
+		}
+		for _, n := range sc.named {
+			if n == tt {
+				return &Type{Kind: Any, Origin: t}
+			}
+		}
+		s := &scope{decl: sc.decl, seen: sc.seen, named: append(sc.named, tt)}
+		return b.typeOf(s, field, tt.Underlying())
+
+	case *types.Pointer:
+		typ := b.typeOf(sc, field, tt.Elem())
+		typ.Nullable = true
+		return typ
+
+	case *types.Slice:
+		return b.listOf(sc, field, tt, tt.Elem())
+
+	case *types.Struct:
+		for _, s := range sc.seen {
+			if s == tt {
+				return &Type{Kind: Any, Origin: t}
+			}
+		}
+		d := &Decl{
+			Path:   sc.decl.Path,
+			Name:   typex.UpperFirst(field),
+			Kind:   Struct,
+			Parent: sc.decl,
+			Origin: t,
+		}
+		sc.decl.Nested = append(sc.decl.Nested, d)
+		d.Fields = b.fields(&scope{decl: d, seen: append(sc.seen, tt), named: sc.named}, tt)
+		return &Type{Kind: Ref, Decl: d, Origin: t}
+	}
+	return &Type{Kind: Any, Origin: t}
+}
+
+func (b *Builder) listOf(sc *scope, field string, t, elem types.Type) *Type {
+	if _, ok := t.(*types.Slice); ok && isByte(elem) {
+		return &Type{Kind: Bytes, Origin: t} // encoding/json encodes []byte as base64 string
+	}
+	return &Type{Kind: List, Elem: b.typeOf(sc, field, elem), Origin: t}
+}
+
+func isByte(t types.Type) bool {
+	b, ok := t.(*types.Basic)
+	return ok && b.Kind() == types.Byte
+}
+
+func basic(t *types.Basic) *Type {
+	typ := &Type{Kind: Any, Origin: t}
+
+	switch t.Kind() {
+	case types.Bool:
+		typ.Kind = Bool
+	case types.String:
+		typ.Kind = String
+	case types.Int, types.Int64:
+		typ.Kind, typ.Bits = Int, 64
+	case types.Int8:
+		typ.Kind, typ.Bits = Int, 8
+	case types.Int16:
+		typ.Kind, typ.Bits = Int, 16
+	case types.Int32:
+		typ.Kind, typ.Bits = Int, 32
+	case types.Uint, types.Uint64, types.Uintptr:
+		typ.Kind, typ.Bits = Uint, 64
+	case types.Uint8:
+		typ.Kind, typ.Bits = Uint, 8
+	case types.Uint16:
+		typ.Kind, typ.Bits = Uint, 16
+	case types.Uint32:
+		typ.Kind, typ.Bits = Uint, 32
+	case types.Float32:
+		typ.Kind, typ.Bits = Float, 32
+	case types.Float64:
+		typ.Kind, typ.Bits = Float, 64
+	}
+	return typ
+}
+
+// builtin returns the type of a well-known named type.
+func builtin(t *types.Named) *Type {
+	if t.Obj().Pkg() == nil || t.Obj().Pkg().Path() != "time" {
+		return nil
+	}
+	switch t.Obj().Name() {
+	case "Time":
+		return &Type{Kind: Time, Origin: t}
+	case "Duration":
+		return &Type{Kind: Int, Bits: 64, Origin: t} // nanoseconds, like encoding/json
+	}
+	return nil
+}
+
+// reaches reports whether a type refers to an alias declaration
+// through other aliases, i.e. whether the alias is recursive.
+func reaches(t *Type, d *Decl, seen []*Decl) bool {
+	if t == nil {
+		return false
+	}
+	if t.Kind == Ref && t.Decl.Kind == Alias {
+		if t.Decl == d {
+			return true
+		}
+		for _, s := range seen {
+			if s == t.Decl {
+				return false
+			}
+		}
+		return reaches(t.Decl.Type, d, append(seen, t.Decl))
+	}
+	return reaches(t.Key, d, seen) || reaches(t.Elem, d, seen)
+}
+
+func (b *Builder) values(t *types.Named, consts []*types.Const) []*Value {
+	vals := make([]*Value, 0, len(consts))
+	for _, c := range consts {
+		n := strings.TrimPrefix(c.Name(), t.Obj().Name())
+		if n == "" {
+			n = c.Name()
+		}
+		vals = append(vals, &Value{Name: n, Const: c.Name(), Value: c.Val(), Doc: b.doc(c)})
+	}
+	return vals
+}
+
+// enumValues returns the constants of a string or integer type.
+func (b *Builder) enumValues(t *types.Named) []*types.Const {
+	bt, ok := t.Underlying().(*types.Basic)
+	if !ok || bt.Info()&(types.IsInteger|types.IsString) == 0 {
+		return nil
+	}
+	return typex.Constants(t, b.IncludeUnexported)
+}
+
+// isTranslatable reports whether a named type has a declaration,
+// interfaces, channels and functions have none.
+func isTranslatable(t *types.Named) bool {
+	switch t.Underlying().(type) {
+	case *types.Chan, *types.Interface, *types.Signature:
+		return false
+	}
+	return true
+}
+
+func (b *Builder) tagKey() string {
+	if b.TagKey != "" {
+		return b.TagKey
+	}
+	return typex.DefaultTagKey
+}
+
+// pathAndName returns the replaced package path of a declaration
+// and the declaration name.
+func (b *Builder) pathAndName(t *types.Named) (p, n string) {
+	p, n = b.names().Split(t.String())
+	return strings.Trim(p, "/"), n
+}
+
+func (b *Builder) doc(obj types.Object) string {
+	if b.DocFunc != nil {
+		return b.DocFunc(obj)
+	}
+	return ""
+}
+
+func (b *Builder) names() typex.Names {
+	return typex.Names{PathReplaceFunc: b.PathReplaceFunc, IncludeUnexported: b.IncludeUnexported}
+}
@@ -0,0 +1,179 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package model
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p1"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+	"github.com/dtgorski/typex/internal/testdata/p6"
+)
+
+func build(t *testing.T, filter string, v interface{}) *Model {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{filter}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(v).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+	b := Builder{
+		PathReplaceFunc: typex.CreatePathReplaceFunc([]string{".*/testdata:"}),
+		DocFunc:         pac.Doc,
+	}
+	return b.Build(types)
+}
+
+func find(m *Model, path, name string) *Decl {
+	for _, d := range m.Decls {
+		if d.Path == path && d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+func TestBuilder_Build(t *testing.T) {
+	m := build(t, `p5\.`, p5.Order{})
+
+	if got := m.Packages(); !reflect.DeepEqual(got, []string{"p2", "p2/p3", "p5"}) {
+		t.Errorf("unexpected %v", got)
+	}
+	order := find(m, "p5", "Order")
+	if order == nil || order.Kind != Struct || len(order.Fields) != 8 {
+		t.Fatal("unexpected")
+	}
+	for _, f := range []struct {
+		i        int
+		wire     string
+		kind     TypeKind
+		nullable bool
+		optional bool
+	}{
+		{0, "id", String, false, false},
+		{2, "level", Ref, true, true},
+		{3, "items", List, false, false},
+		{4, "labels", Map, false, false},
+		{5, "created", Time, false, false},
+		{7, "ref", Ref, true, false},
+	} {
+		fld := order.Fields[f.i]
+		if fld.Wire != f.wire || fld.Type.Kind != f.kind || fld.Type.Nullable != f.nullable || fld.Optional != f.optional {
+			t.Errorf("unexpected %s", fld.Name)
+		}
+	}
+	if order.Fields[2].Type.Decl != find(m, "p5", "Level") {
+		t.Error("unexpected")
+	}
+
+	if order.Doc != "Order is a customer order." || order.Fields[0].Doc != "ID is the order number." {
+		t.Errorf("unexpected %q", order.Doc)
+	}
+
+	level := find(m, "p5", "Level")
+	if level.Kind != Enum || level.Type.Kind != Uint || level.Type.Bits != 8 || len(level.Values) != 3 {
+		t.Fatal("unexpected")
+	}
+	if v := level.Values[2]; v.Name != "Max" || v.Const != "LevelMax" || v.Value.ExactString() != "1" {
+		t.Errorf("unexpected %v", v)
+	}
+}
+
+func TestBuilder_Nested(t *testing.T) {
+	m := build(t, `p1\.(D|U)$`, p1.D{})
+
+	d := find(m, "p1", "D")
+	if d == nil || len(d.Nested) != 1 {
+		t.Fatal("unexpected")
+	}
+	k := d.Nested[0].Nested[0]
+	if k.Qualified(".") != "D.H.K" || k.Flat() != "DHK" || k.Parent != d.Nested[0] {
+		t.Errorf("unexpected %s", k.Qualified("."))
+	}
+
+	u := find(m, "p1", "U")
+	if u.Kind != Alias || u.Type.Kind != List || u.Type.Elem.Decl != u.Nested[0] {
+		t.Fatal("unexpected")
+	}
+	if n := u.Nested[0].Qualified("."); n != "UItem" {
+		t.Errorf("unexpected %s", n)
+	}
+	if typ := Unalias(&Type{Kind: Ref, Decl: u, Nullable: true}, false); typ.Kind != List || !typ.Nullable {
+		t.Error("unexpected")
+	}
+}
+
+func TestBuilder_Embedded(t *testing.T) {
+	m := build(t, `p1\.T$`, p1.T{})
+
+	wires := make([]string, 0)
+	for _, f := range find(m, "p1", "T").Fields {
+		wires = append(wires, f.Wire)
+	}
+	// D and p3.Y are flattened, their conflicting R fields dropped.
+	want := []string{"-", "H", "other", "W", "U", "V", "X", "M", "N", "O", "P", "Q", "S", "Z"}
+	if !reflect.DeepEqual(wires, want) {
+		t.Errorf("unexpected %v", wires)
+	}
+}
+
+func TestBuilder_TagRules(t *testing.T) {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p6\.`}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(p6.Tagged{}).PkgPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string][]string{
+		// Base is flattened, the tag-named Meta and Extra are not.
+		"json": {"id", "meta", "extra", "name"},
+		// Extra is inlined, yaml flattens no untagged embedded struct.
+		"yaml": {"base", "meta", "label", "name"},
+	} {
+		b := Builder{
+			PathReplaceFunc: typex.CreatePathReplaceFunc([]string{".*/testdata:"}),
+			TagKey:          key,
+		}
+		wires := make([]string, 0)
+		for _, f := range find(b.Build(types), "p6", "Tagged").Fields[:4] {
+			wires = append(wires, f.Wire)
+		}
+		if !reflect.DeepEqual(wires, want) {
+			t.Errorf("unexpected %s %v", key, wires)
+		}
+	}
+}
+
+func TestBuilder_NestedNonASCII(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", `package p
+type Ärger struct {
+	ünter struct{ X int }
+}`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := Builder{IncludeUnexported: true}
+	m := b.Build(typex.TypeMap{"p.Ärger": pkg.Scope().Lookup("Ärger").Type()})
+
+	d := find(m, "p", "Ärger")
+	if d == nil || len(d.Nested) != 1 {
+		t.Fatal("unexpected")
+	}
+	if n := d.Nested[0].Name; n != "Ünter" || !utf8.ValidString(n) {
+		t.Errorf("unexpected %q", n)
+	}
+}
@@ -0,0 +1,172 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package model
+
+import (
+	"go/constant"
+	"go/types"
+	"strings"
+)
+
+type (
+	// Model is a language-neutral projection of the serializable
+	// aspects of a TypeMap: declarations, fields with their wire
+	// names and optionality, enumerations and references. Renderers
+	// of target languages consume the Model instead of deriving the
+	// encoding/json semantics from the Go types on their own.
+	Model struct {
+		// Decls are ordered by path and name, or by the Ranking
+		// of the Builder.
+		Decls []*Decl
+	}
+
+	// Decl is a type declaration. Declarations of anonymous structs
+	// are nested in the declaration enclosing them.
+	Decl struct {
+		Path   string // replaced package path, e.g. "p5"
+		Name   string
+		Kind   DeclKind
+		Fields []*Field // Struct
+		Values []*Value // Enum
+		Type   *Type    // Enum base type, Alias target type
+		Parent *Decl    // enclosing declaration of an anonymous struct
+		Nested []*Decl
+		Doc    string // doc comment text
+		Origin types.Type
+	}
+
+	// Field is a serialized struct field. Fields of embedded structs
+	// are flattened into the enclosing struct and conflicting names
+	// are resolved like encoding/json does.
+	Field struct {
+		Name     string // Go field name
+		Wire     string // serialized name
+		Type     *Type
+		Optional bool // omitted when empty, i.e. "omitempty"
+		Doc      string
+		Origin   *types.Var
+	}
+
+	// Value is an enumeration value.
+	Value struct {
+		Name  string // constant name without the type name prefix
+		Const string // constant name
+		Value constant.Value
+		Doc   string
+	}
+
+	// Type is a type reference.
+	Type struct {
+		Kind     TypeKind
+		Bits     int   // Int, Uint, Float
+		Elem     *Type // List, Map
+		Key      *Type // Map
+		Decl     *Decl // Ref
+		Nullable bool  // Go pointer
+		Origin   types.Type
+	}
+
+	// DeclKind is the kind of a declaration.
+	DeclKind int
+
+	// TypeKind is the kind of a type reference.
+	TypeKind int
+)
+
+const (
+	// Struct is a record of fields.
+	Struct DeclKind = iota
+	// Enum is an integer or string type with typed constants.
+	Enum
+	// Alias is any other named type.
+	Alias
+)
+
+const (
+	// Any is a type without serializable counterpart, e.g. an
+	// interface, a channel or a function.
+	Any TypeKind = iota
+	Bool
+	Int
+	Uint
+	Float
+	String
+	Bytes // base64 encoded string
+	Time  // RFC 3339 string
+	List
+	Map
+	Ref
+)
+
+// Packages returns the paths of the declarations in order.
+func (m *Model) Packages() []string {
+	paths := make([]string, 0)
+	for _, d := range m.Decls {
+		if len(paths) == 0 || paths[len(paths)-1] != d.Path {
+			paths = append(paths, d.Path)
+		}
+	}
+	return paths
+}
+
+// Package returns the declarations of a path in order.
+func (m *Model) Package(path string) []*Decl {
+	decls := make([]*Decl, 0)
+	for _, d := range m.Decls {
+		if d.Path == path {
+			decls = append(decls, d)
+		}
+	}
+	return decls
+}
+
+// Qualified returns the name of a declaration qualified by the
+// names of the enclosing declarations, joined by sep. Aliases do
+// not enclose declarations, their name becomes a prefix instead.
+func (d *Decl) Qualified(sep string) string {
+	switch {
+	case d.Parent == nil:
+		return d.Name
+	case d.Parent.Kind == Alias:
+		return d.Parent.Name + d.Name
+	}
+	return d.Parent.Qualified(sep) + sep + d.Name
+}
+
+// Flat returns the concatenated names of a declaration and its
+// enclosing declarations, e.g. "OrderMeta", for languages without
+// nested declarations.
+func (d *Decl) Flat() string {
+	return d.Qualified("")
+}
+
+// Segments returns the path segments of a declaration.
+func (d *Decl) Segments() []string {
+	parts := make([]string, 0)
+	for _, s := range strings.Split(d.Path, "/") {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return parts
+}
+
+// Nullable reports whether a field may be null or absent.
+func (f *Field) Nullable() bool {
+	return f.Optional || f.Type.Nullable
+}
+
+// Unalias returns the target type of a reference to an alias. A
+// nullable reference yields a nullable target. References to enums
+// resolve to the enum base type when base is set.
+func Unalias(t *Type, base bool) *Type {
+	for i := 0; t.Kind == Ref && i < 32; i++ {
+		if t.Decl.Kind == Struct || t.Decl.Kind == Enum && !base {
+			break
+		}
+		u := *t.Decl.Type
+		u.Nullable = u.Nullable || t.Nullable
+		t = &u
+	}
+	return t
+}
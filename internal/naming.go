@@ -0,0 +1,167 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Names resolves the names of declared types for the renderers,
+// honoring the path replacement and the export policy.
+type Names struct {
+	PathReplaceFunc   PathReplaceFunc
+	IncludeUnexported bool
+}
+
+// Replace returns the replaced qualified name of a type.
+func (n Names) Replace(s string) string {
+	if n.PathReplaceFunc != nil {
+		return n.PathReplaceFunc(s)
+	}
+	return s
+}
+
+// Split returns the replaced package path and the name of a
+// qualified type name, e.g. "p5" and "Order" of "p5.Order". The
+// type arguments of an instantiated generic type are qualified by
+// their package names, e.g. "Pair[p6.Color, *p6.Node]".
+func (n Names) Split(s string) (path, name string) {
+	name = n.Replace(s)
+	if i := strings.LastIndex(base(name), "."); i > -1 && i < len(name)-1 {
+		return name[:i], shortArgs(name[i+1:])
+	}
+	return "", shortArgs(name)
+}
+
+// PathAndName returns the PathMap key of a qualified type name,
+// e.g. "p5/Order", and the name of the type.
+func (n Names) PathAndName(s string) (p, name string) {
+	p, name = n.Split(s)
+	if p == "" {
+		return name, name
+	}
+	return p + "/" + name, name
+}
+
+// IsExported reports whether the last segment of a possibly
+// qualified name is exported or unexported names are included.
+func (n Names) IsExported(s string) bool {
+	if n.IncludeUnexported {
+		return true
+	}
+	s = strings.ReplaceAll(base(s), ".", "/")
+	return token.IsExported(s[strings.LastIndex(s, "/")+1:])
+}
+
+// shortArgs qualifies the type arguments of a type name by the
+// last segment of their package paths.
+func shortArgs(s string) string {
+	i := strings.IndexByte(s, '[')
+	if i < 0 {
+		return s
+	}
+	return s[:i] + qualified.ReplaceAllStringFunc(s[i:], func(q string) string {
+		return q[strings.LastIndex(q[:strings.LastIndex(q, ".")], "/")+1:]
+	})
+}
+
+// base returns a type name without type arguments.
+func base(s string) string {
+	if i := strings.IndexByte(s, '['); i > -1 {
+		return s[:i]
+	}
+	return s
+}
+
+// Constants returns the typed constants of a named type declared in
+// its package, ordered by their position in the source. The result
+// is the basis for enumerations in target languages.
+func Constants(t *types.Named, includeUnexported bool) []*types.Const {
+	consts := make([]*types.Const, 0)
+	if t.Obj().Pkg() == nil {
+		return consts
+	}
+	scope := t.Obj().Pkg().Scope()
+
+	for _, n := range scope.Names() {
+		c, ok := scope.Lookup(n).(*types.Const)
+		if !ok || !types.Identical(c.Type(), t) {
+			continue
+		}
+		if includeUnexported || token.IsExported(n) {
+			consts = append(consts, c)
+		}
+	}
+	sort.SliceStable(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+	return consts
+}
+
+// EnumName returns the name of an enumeration value derived from
+// a constant name without the type name prefix, in upper snake
+// case, e.g. "StatusOpen" of type "Status" becomes "OPEN".
+func EnumName(typeName, constName string) string {
+	n := strings.TrimPrefix(constName, typeName)
+	if n == "" {
+		n = constName
+	}
+	return strings.ToUpper(SnakeCase(n))
+}
+
+// SnakeCase converts a Go identifier to snake case, e.g.
+// "HTTPServer" to "http_server" and "Int8Type" to "int8_type".
+func SnakeCase(s string) string {
+	b, rs := strings.Builder{}, []rune(s)
+	for i, c := range rs {
+		if unicode.IsUpper(c) && i > 0 && rs[i-1] != '_' &&
+			(!unicode.IsUpper(rs[i-1]) || i+1 < len(rs) && unicode.IsLower(rs[i+1])) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
+
+// LowerCamelCase converts a Go identifier to lower camel case,
+// e.g. "HTTPServer" to "httpServer" and "ID" to "id".
+func LowerCamelCase(s string) string {
+	rs := []rune(s)
+	for i := range rs {
+		if !unicode.IsUpper(rs[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(rs) && unicode.IsLower(rs[i+1]) {
+			break
+		}
+		rs[i] = unicode.ToLower(rs[i])
+	}
+	return string(rs)
+}
+
+// Abbreviate returns a Go type expression with the bodies of
+// anonymous structs and interfaces left out, qualified by the
+// package names, e.g. "interface{...}" or "*p2.S".
+func Abbreviate(t types.Type) string {
+	s := types.TypeString(t, (*types.Package).Name)
+	b, depth := strings.Builder{}, 0
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '{' && depth == 0 && s[i+1] != '}':
+			b.WriteString("{...}")
+			depth++
+		case c == '{' && depth > 0:
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"testing"
+)
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"ID":          "id",
+		"HTTPServer":  "http_server",
+		"Int8Type":    "int8_type",
+		"UintPtrType": "uint_ptr_type",
+		"userName":    "user_name",
+	} {
+		if got := SnakeCase(in); got != want {
+			t.Errorf("unexpected %q, expected %q", got, want)
+		}
+	}
+}
+
+func TestEnumName(t *testing.T) {
+	if got := EnumName("Status", "StatusOpen"); got != "OPEN" {
+		t.Errorf("unexpected %q", got)
+	}
+	if got := EnumName("Level", "Level"); got != "LEVEL" {
+		t.Errorf("unexpected %q", got)
+	}
+	if got := EnumName("Level", "MaxLevel"); got != "MAX_LEVEL" {
+		t.Errorf("unexpected %q", got)
+	}
+}
+
+func TestLowerCamelCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"ID":         "id",
+		"HTTPServer": "httpServer",
+		"UserID":     "userID",
+		"Int8Type":   "int8Type",
+		"userName":   "userName",
+	} {
+		if got := LowerCamelCase(in); got != want {
+			t.Errorf("unexpected %q, expected %q", got, want)
+		}
+	}
+}
+
+func TestNames_Split(t *testing.T) {
+	n := Names{PathReplaceFunc: CreatePathReplaceFunc([]string{".*/testdata:"})}
+
+	p, name := n.Split("github.com/a/testdata/p6.Pair[github.com/a/testdata/p6.Color, *github.com/a/testdata/p6.Node]")
+	if p != "p6" || name != "Pair[p6.Color, *p6.Node]" {
+		t.Errorf("unexpected %q %q", p, name)
+	}
+	p, name = Names{}.Split("github.com/a/p6.Pair[map[string]github.com/a/p6.Pair[int, time.Time], []*github.com/b/p7.Node]")
+	if p != "github.com/a/p6" || name != "Pair[map[string]p6.Pair[int, time.Time], []*p7.Node]" {
+		t.Errorf("unexpected %q %q", p, name)
+	}
+	if !n.IsExported("p6.Pair[p6.x]") || n.IsExported("p6.pair[p6.X]") {
+		t.Error("unexpected")
+	}
+}
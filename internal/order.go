@@ -0,0 +1,148 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/token"
+	"go/types"
+	"math"
+	"sort"
+)
+
+type (
+	// Order is the sequence of the packages and the declarations in the
+	// output. Packages follow the first of their declarations.
+	Order string
+
+	// Ranking is the position of the qualified type names of a TypeMap
+	// in an Order. A nil Ranking leaves the alphabetical order in place.
+	Ranking map[string]int
+)
+
+// Orders of declarations.
+const (
+	OrderName   Order = "name"   // alphabetical
+	OrderSource Order = "source" // by source position
+	OrderDeps   Order = "deps"   // topological, dependencies first
+)
+
+// Rank returns the Ranking of the types of a TypeMap in the order o.
+// The source positions are provided by f. Types in a dependency cycle
+// are ranked in the order they are reached.
+func Rank(m TypeMap, o Order, f SourceFunc) Ranking {
+	keys := make([]string, 0, len(m))
+	for s := range m {
+		keys = append(keys, s)
+	}
+	sort.Strings(keys)
+
+	switch o {
+	case OrderSource:
+		pos := make(map[string]token.Position, len(keys))
+		for _, s := range keys {
+			if nt, ok := m[s].(*types.Named); ok && f != nil {
+				pos[s] = f(nt)
+			}
+		}
+		sort.SliceStable(keys, func(i, j int) bool {
+			a, b := pos[keys[i]], pos[keys[j]]
+			if a.Filename == "" || b.Filename == "" {
+				return a.Filename != "" && b.Filename == ""
+			}
+			if a.Filename != b.Filename {
+				return a.Filename < b.Filename
+			}
+			return a.Offset < b.Offset
+		})
+	case OrderDeps:
+		keys = dependenciesFirst(m, keys)
+	default:
+		return nil
+	}
+
+	r := make(Ranking, len(keys))
+	for i, s := range keys {
+		r[s] = i
+	}
+	return r
+}
+
+// dependenciesFirst returns the keys of a TypeMap sorted topologically,
+// the referenced types of a declaration precede the declaration.
+func dependenciesFirst(m TypeMap, keys []string) []string {
+	res := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+
+	var visit func(s string)
+	visit = func(s string) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+		if nt, ok := m[s].(*types.Named); ok {
+			for _, ref := range References(nt, true) {
+				if to := ref.To.String(); m[to] != nil {
+					visit(to)
+				}
+			}
+		}
+		res = append(res, s)
+	}
+	for _, s := range keys {
+		visit(s)
+	}
+	return res
+}
+
+// Of returns the rank of a qualified type name. Names
+// without rank follow the ranked ones.
+func (r Ranking) Of(s string) int {
+	if i, ok := r[s]; ok {
+		return i
+	}
+	return math.MaxInt
+}
+
+// Put records the rank of a key, unless the key has a lower rank.
+func (r Ranking) Put(s string, i int) {
+	if j, ok := r[s]; !ok || i < j {
+		r[s] = i
+	}
+}
+
+// Sort orders the keys by their rank, keys of equal rank keep
+// their order. Keys without rank follow the ranked ones.
+func (r Ranking) Sort(keys []string) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return r.Of(keys[i]) < r.Of(keys[j])
+	})
+}
+
+// Rekey returns the Ranking under the keys derived by f from the type
+// names, e.g. the keys of a PathMap. Keys derived from several names
+// get the lowest rank.
+func (r Ranking) Rekey(f func(string) string) Ranking {
+	if r == nil {
+		return nil
+	}
+	res := make(Ranking, len(r))
+	for s, i := range r {
+		res.Put(f(s), i)
+	}
+	return res
+}
+
+// FieldOrder returns the indices of the fields of a struct in source
+// order, or ordered by the Go names of the fields when sorted is set.
+func FieldOrder(st *types.Struct, sorted bool) []int {
+	idx := make([]int, st.NumFields())
+	for i := range idx {
+		idx[i] = i
+	}
+	if sorted {
+		sort.SliceStable(idx, func(i, j int) bool {
+			return st.Field(idx[i]).Name() < st.Field(idx[j]).Name()
+		})
+	}
+	return idx
+}
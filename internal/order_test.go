@@ -0,0 +1,84 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	gotypes "go/types"
+	"reflect"
+	"testing"
+
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func TestRank(t *testing.T) {
+	pkgPath := reflect.TypeOf(p5.Order{}).PkgPath()
+
+	pac := Packagist{
+		PathFilterFunc: CreatePathFilterFunc([]string{`p5\.Order$`}, nil),
+	}
+	types, err := pac.Inspect(pkgPath)
+	if err != nil {
+		t.Error("unexpected")
+		return
+	}
+	if r := Rank(types, OrderName, pac.Source); r != nil {
+		t.Errorf("unexpected %v", r)
+	}
+
+	order, status, level, item := pkgPath+".Order", pkgPath+".Status", pkgPath+".Level", pkgPath+".Item"
+
+	r := Rank(types, OrderSource, pac.Source)
+	if !(r.Of(status) < r.Of(level) && r.Of(level) < r.Of(order) && r.Of(order) < r.Of(item)) {
+		t.Errorf("unexpected %v", r)
+	}
+	r = Rank(types, OrderDeps, pac.Source)
+	for _, s := range []string{status, level, item, "time.Time"} {
+		if r.Of(s) > r.Of(order) {
+			t.Errorf("unexpected %s after %s", s, order)
+		}
+	}
+	if len(r) != len(types) {
+		t.Errorf("unexpected %d", len(r))
+	}
+}
+
+func TestRanking_Sort(t *testing.T) {
+	keys := []string{"a", "b", "c", "d"}
+	Ranking(nil).Sort(keys)
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c", "d"}) {
+		t.Errorf("unexpected %v", keys)
+	}
+	r := Ranking{"c": 0, "a": 1}
+	r.Sort(keys)
+	if !reflect.DeepEqual(keys, []string{"c", "a", "b", "d"}) {
+		t.Errorf("unexpected %v", keys)
+	}
+}
+
+func TestRanking_Rekey(t *testing.T) {
+	if r := Ranking(nil).Rekey(nil); r != nil {
+		t.Errorf("unexpected %v", r)
+	}
+	r := Ranking{"a/v1.T": 2, "a/v2.T": 1, "a/v1.U": 0}
+	f := CreatePathReplaceFunc([]string{"/v[12]:"})
+
+	if got := r.Rekey(f); !reflect.DeepEqual(got, Ranking{"a.T": 1, "a.U": 0}) {
+		t.Errorf("unexpected %v", got)
+	}
+}
+
+func TestFieldOrder(t *testing.T) {
+	fields := []*gotypes.Var{
+		gotypes.NewField(0, nil, "b", gotypes.Typ[gotypes.Int], false),
+		gotypes.NewField(0, nil, "C", gotypes.Typ[gotypes.Int], false),
+		gotypes.NewField(0, nil, "a", gotypes.Typ[gotypes.Int], false),
+	}
+	s := gotypes.NewStruct(fields, nil)
+
+	if got := FieldOrder(s, false); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("unexpected %v", got)
+	}
+	if got := FieldOrder(s, true); !reflect.DeepEqual(got, []int{1, 2, 0}) {
+		t.Errorf("unexpected %v", got)
+	}
+}
@@ -3,9 +3,13 @@
 package internal
 
 import (
-	"errors"
+	"fmt"
+	"go/ast"
 	"go/token"
 	"go/types"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -16,11 +20,52 @@ type (
 	Packagist struct {
 		PackageLoaderFunc PackageLoaderFunc
 		PathFilterFunc    PathFilterFunc
+		StopFilterFunc    PathFilterFunc
+		TypeFilterFunc    TypeFilterFunc
 
 		IncludeUnexported bool
 		IncludeTestFiles  bool
 
+		// SelectMarked restricts the filtered types to
+		// those annotated with an export Directive.
+		SelectMarked bool
+
+		// MaxDepth limits the number of hops from the filtered
+		// types to their collected dependencies, 0 means no limit.
+		MaxDepth int
+
+		// Partial continues with the packages loaded without
+		// errors, the errors of the other packages are kept
+		// as Diagnostics instead of failing the inspection.
+		Partial bool
+
+		// Contexts are the build configurations the packages are
+		// inspected in, the types of all contexts are merged. No
+		// context means the default configuration of the go command.
+		Contexts []BuildContext
+
+		// Env holds KEY=VAL variables added to the environment
+		// of the go command in all contexts.
+		Env []string
+
+		// Overlay maps absolute file paths to the source read
+		// instead of the content of the file, if any.
+		Overlay map[string][]byte
+
+		// Cache stores the types of the loaded packages, if set.
+		Cache *Cache
+
 		typeMap TypeMap
+		depth   map[string]int
+		docs    map[string]string
+		notes   map[string]string
+		dirs    Directives
+		imports map[string]*types.Package
+		named   []*types.Named
+		fset    *token.FileSet
+		modDirs []string
+		diags   Diagnostics
+		divs    []Divergence
 	}
 
 	// PackageLoaderFunc returns the Go packages named by the given patterns.
@@ -32,6 +77,15 @@ type (
 	// PathReplaceFunc is a simple string replacement function.
 	PathReplaceFunc func(string) string
 
+	// PositionFunc returns the source position of a named type.
+	PositionFunc func(*types.Named) string
+
+	// SourceFunc returns the source position of a named type.
+	SourceFunc func(*types.Named) token.Position
+
+	// DocFunc returns the doc comment text of a declared object.
+	DocFunc func(types.Object) string
+
 	// QualifierFunc returns the type qualifier (path) of a package.
 	QualifierFunc func(p types.Package) string
 
@@ -41,43 +95,295 @@ type (
 
 // Inspect finds matching types in the packages named by the given patterns.
 func (p *Packagist) Inspect(patterns ...string) (TypeMap, error) {
+	if p.init(); len(patterns) == 0 {
+		return p.typeMap, nil
+	}
+	contexts := p.contexts()
+	maps := make([]TypeMap, 0, len(contexts))
+
+	for _, ctx := range contexts {
+		p.typeMap = make(TypeMap)
+		p.depth = make(map[string]int)
+		p.imports = make(map[string]*types.Package)
+
+		pkgs, err := p.load(ctx, patterns...)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range pkgs {
+			p.filter(pkg)
+		}
+		maps = append(maps, p.typeMap)
+	}
+	p.typeMap, p.divs = merge(contexts, maps)
+	return p.typeMap, nil
+}
+
+// Divergences returns the types declared differently in the
+// Contexts of the inspection, ordered by type name.
+func (p *Packagist) Divergences() []Divergence {
+	return p.divs
+}
+
+func (p *Packagist) contexts() []BuildContext {
+	if len(p.Contexts) == 0 {
+		return []BuildContext{{}}
+	}
+	return p.Contexts
+}
+
+func (p *Packagist) init() {
 	if p.PackageLoaderFunc == nil {
 		p.PackageLoaderFunc = packages.Load
 	}
 	if p.PathFilterFunc == nil {
 		p.PathFilterFunc = func(string) bool { return true }
 	}
-	if p.typeMap = make(TypeMap); len(patterns) == 0 {
-		return p.typeMap, nil
+	if p.StopFilterFunc == nil {
+		p.StopFilterFunc = func(string) bool { return false }
 	}
-	pkgs, err := p.load(patterns...)
-	if err != nil {
-		return nil, err
-	}
-	for _, pkg := range pkgs {
-		p.filter(pkg)
-	}
-	return p.typeMap, nil
+	p.typeMap = make(TypeMap)
+	p.depth = make(map[string]int)
+	p.docs = make(map[string]string)
+	p.notes = make(map[string]string)
+	p.dirs = make(Directives)
+	p.named = make([]*types.Named, 0)
+	p.modDirs = make([]string, 0)
+	p.diags = make(Diagnostics, 0)
+	p.imports = make(map[string]*types.Package)
+	p.fset = token.NewFileSet()
+	p.divs = nil
 }
 
-func (p *Packagist) load(patterns ...string) ([]*packages.Package, error) {
-	mode := packages.NeedTypes
+func (p *Packagist) load(ctx BuildContext, patterns ...string) ([]*packages.Package, error) {
+	mode := packages.NeedName
+	mode |= packages.NeedTypes
 	mode |= packages.NeedTypesInfo
-	conf := &packages.Config{Mode: mode, Tests: p.IncludeTestFiles}
+	mode |= packages.NeedSyntax
+	mode |= packages.NeedModule
+	conf := &packages.Config{
+		Mode:       mode,
+		Tests:      p.IncludeTestFiles,
+		Fset:       p.fset, // shared by the contexts
+		Env:        ctx.environ(p.Env),
+		BuildFlags: ctx.buildFlags(),
+		Overlay:    p.Overlay,
+	}
 
-	pkgs, err := p.PackageLoaderFunc(conf, patterns...)
+	patterns, srcs, err := p.sources(ctx, patterns)
 	if err != nil {
 		return nil, err
 	}
+	pkgs := make([]*packages.Package, 0)
+	if len(patterns) > 0 {
+		if pkgs, err = p.loadPackages(ctx, conf, patterns); err != nil {
+			return nil, err
+		}
+	}
+	for _, src := range srcs {
+		pkg, err := p.check(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	valid := make([]*packages.Package, 0, len(pkgs))
 	for _, pkg := range pkgs {
 		if len(pkg.Errors) > 0 {
-			err := errors.New(pkg.Errors[0].Msg)
-			return nil, err
+			p.diags = p.diags.add(diagnose(pkg))
+			continue
+		}
+		valid = append(valid, pkg)
+	}
+	if len(p.diags) > 0 && !p.Partial {
+		return nil, p.diags
+	}
+	pkgs = valid
+
+	for _, pkg := range pkgs {
+		p.collectDecls(pkg)
+		p.collectImports(pkg.Types)
+
+		if pkg.Module != nil && !p.hasModDir(pkg.Module.Dir) {
+			p.modDirs = append(p.modDirs, pkg.Module.Dir)
 		}
 	}
 	return pkgs, nil
 }
 
+func (p *Packagist) hasModDir(dir string) bool {
+	for _, d := range p.modDirs {
+		if d == dir {
+			return true
+		}
+	}
+	return dir == ""
+}
+
+// Position returns the source position ("file:line") of the named type.
+// The file is relative to the module root of the inspected packages if
+// the type has been declared within, otherwise it is an absolute path.
+func (p *Packagist) Position(t *types.Named) string {
+	if p.fset == nil || !t.Obj().Pos().IsValid() {
+		return ""
+	}
+	pos := p.fset.Position(t.Obj().Pos())
+	file := filepath.ToSlash(pos.Filename)
+
+	for _, dir := range p.modDirs {
+		if rel, err := filepath.Rel(dir, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			file = filepath.ToSlash(rel)
+			break
+		}
+	}
+	return fmt.Sprintf("%s:%d", file, pos.Line)
+}
+
+// Source returns the source position of the named type, the zero
+// position if the type has been declared outside of any source file.
+func (p *Packagist) Source(t *types.Named) token.Position {
+	if p.fset == nil || !t.Obj().Pos().IsValid() {
+		return token.Position{}
+	}
+	return p.fset.Position(t.Obj().Pos())
+}
+
+// Diagnostics returns the errors of the packages
+// left out of a Partial inspection.
+func (p *Packagist) Diagnostics() Diagnostics {
+	return p.diags
+}
+
+// Doc returns the doc comment text of a type, a struct field or a
+// constant declared in the inspected packages. Fields and constants
+// without doc comment yield their trailing line comment, if any.
+func (p *Packagist) Doc(obj types.Object) string {
+	return strings.TrimSpace(p.notes[p.site(obj.Pos(), obj.Name())])
+}
+
+// Directives returns the directives found in the doc comments
+// of the type declarations of the inspected packages.
+func (p *Packagist) Directives() Directives {
+	return p.dirs
+}
+
+func (p *Packagist) collectDecls(pkg *packages.Package) {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch gen.Tok {
+			case token.TYPE:
+				for _, spec := range gen.Specs {
+					p.collectType(pkg, gen, spec.(*ast.TypeSpec))
+				}
+			case token.CONST:
+				for _, spec := range gen.Specs {
+					vs := spec.(*ast.ValueSpec)
+					doc := vs.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					for _, n := range vs.Names {
+						p.note(n, doc, vs.Comment)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (p *Packagist) collectType(pkg *packages.Package, gen *ast.GenDecl, ts *ast.TypeSpec) {
+	ast.Inspect(ts.Type, func(n ast.Node) bool {
+		if f, ok := n.(*ast.Field); ok {
+			for _, id := range f.Names {
+				p.note(id, f.Doc, f.Comment)
+			}
+		}
+		return true
+	})
+
+	doc := ts.Doc
+	if doc == nil && len(gen.Specs) == 1 {
+		doc = gen.Doc
+	}
+	if doc == nil {
+		return
+	}
+	name := pkg.Types.Path() + "." + ts.Name.Name
+	p.docs[name] = doc.Text()
+	p.notes[p.site(ts.Name.Pos(), ts.Name.Name)] = doc.Text()
+
+	for _, c := range doc.List {
+		if d, ok := ParseDirective(c.Text); ok {
+			p.dirs[name] = append(p.dirs[name], d)
+		}
+	}
+}
+
+// note records the doc comment, or else the line comment, of a
+// declared object identified by id.
+func (p *Packagist) note(id *ast.Ident, doc, line *ast.CommentGroup) {
+	switch {
+	case doc != nil:
+		p.notes[p.site(id.Pos(), id.Name)] = doc.Text()
+	case line != nil:
+		p.notes[p.site(id.Pos(), id.Name)] = line.Text()
+	}
+}
+
+// site returns the key of the notes of a declared object, its name
+// at the file and line of its position. Unlike token.Pos, the key
+// matches the objects of the syntax and of types read from a Cache.
+func (p *Packagist) site(pos token.Pos, name string) string {
+	at := p.fset.Position(pos)
+	return at.Filename + ":" + strconv.Itoa(at.Line) + ":" + name
+}
+
+func (p *Packagist) collectImports(pkg *types.Package) {
+	if _, ok := p.imports[pkg.Path()]; ok {
+		return
+	}
+	p.imports[pkg.Path()] = pkg
+	for _, imp := range pkg.Imports() {
+		p.collectImports(imp)
+	}
+}
+
+// lookup resolves a qualified type name within the loaded packages
+// and their imports. The package path may be abbreviated by leaving
+// out leading path segments, e.g. "money.Amount".
+func (p *Packagist) lookup(name string) types.Type {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		if obj, ok := types.Universe.Lookup(name).(*types.TypeName); ok {
+			return obj.Type()
+		}
+		return nil
+	}
+	path, name := name[:i], name[i+1:]
+	if pkg, ok := p.imports[path]; ok {
+		if obj, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok {
+			return obj.Type()
+		}
+	}
+	paths := make([]string, 0)
+	for s := range p.imports {
+		if strings.HasSuffix(s, "/"+path) {
+			paths = append(paths, s)
+		}
+	}
+	sort.Strings(paths)
+	for _, s := range paths {
+		if obj, ok := p.imports[s].Scope().Lookup(name).(*types.TypeName); ok {
+			return obj.Type()
+		}
+	}
+	return nil
+}
+
 func (p *Packagist) filter(pkg *packages.Package) {
 	scope := pkg.Types.Scope()
 
@@ -86,77 +392,120 @@ func (p *Packagist) filter(pkg *packages.Package) {
 			continue
 		}
 		obj := scope.Lookup(name)
+		if tn, ok := obj.(*types.TypeName); ok && !tn.IsAlias() {
+			if t, ok := tn.Type().(*types.Named); ok {
+				p.named = append(p.named, t)
+			}
+		}
 		path := obj.Pkg().Path() + "." + name
 		if !p.PathFilterFunc(path) {
 			continue
 		}
-		p.visit(obj.Type())
+		if p.SelectMarked && !p.dirs.IsMarked(path) {
+			continue
+		}
+		if p.TypeFilterFunc != nil {
+			tn, ok := obj.(*types.TypeName)
+			if !ok || !p.TypeFilterFunc(TypeInfo{tn, p.docs[path], p.lookup}) {
+				continue
+			}
+		}
+		if _, ok := obj.(*types.TypeName); ok {
+			p.visit(obj.Type(), 0)
+		} else {
+			p.visit(obj.Type(), 1)
+		}
 	}
 }
 
-func (p *Packagist) visit(t types.Type) {
-	switch tt := t.(type) {
+func (p *Packagist) visit(t types.Type, depth int) {
+	switch tt := Unalias(t).(type) {
 	case *types.Array:
-		p.visit(tt.Elem())
+		p.visit(tt.Elem(), depth)
 
 	case *types.Chan:
-		p.visit(tt.Elem())
+		p.visit(tt.Elem(), depth)
 
 	case *types.Interface:
-		p.visitInterface(tt)
+		p.visitInterface(tt, depth)
 
 	case *types.Map:
-		p.visit(tt.Key())
-		p.visit(tt.Elem())
+		p.visit(tt.Key(), depth)
+		p.visit(tt.Elem(), depth)
 
 	case *types.Named:
+		if tt.TypeParams().Len() > tt.TypeArgs().Len() {
+			return // uninstantiated generic type
+		}
 		s := tt.String()
-		if _, ok := p.typeMap[s]; !ok {
-			p.typeMap[s] = tt
-			p.visit(tt.Underlying())
+		if d, ok := p.depth[s]; ok && d <= depth {
+			return
+		}
+		if !p.isCollectable(tt, depth) {
+			return
 		}
+		p.typeMap[s] = tt
+		p.depth[s] = depth
+		p.visit(tt.Underlying(), depth+1)
+
 	case *types.Pointer:
-		p.visit(tt.Elem())
+		p.visit(tt.Elem(), depth)
 
 	case *types.Signature:
-		p.visit(tt.Params())
-		p.visit(tt.Results())
+		p.visit(tt.Params(), depth)
+		p.visit(tt.Results(), depth)
 
 	case *types.Slice:
-		p.visit(tt.Elem())
+		p.visit(tt.Elem(), depth)
 
 	case *types.Struct:
-		p.visitStruct(tt)
+		p.visitStruct(tt, depth)
 
 	case *types.Tuple:
 		for i, n := 0, tt.Len(); i < n; i++ {
-			p.visit(tt.At(i).Type())
+			p.visit(tt.At(i).Type(), depth)
 		}
 	}
 }
 
-func (p *Packagist) visitInterface(t *types.Interface) {
+func (p *Packagist) visitInterface(t *types.Interface, depth int) {
 	for i, n := 0, t.NumEmbeddeds(); i < n; i++ {
-		nn := t.EmbeddedType(i).(*types.Named)
-		if p.isExported(nn.String()) {
-			p.visit(t.EmbeddedType(i))
+		// unions and approximations of constraints are skipped
+		nn, ok := t.EmbeddedType(i).(*types.Named)
+		if ok && p.isExported(nn.String()) {
+			p.visit(t.EmbeddedType(i), depth)
 		}
 	}
 	for i, n := 0, t.NumMethods(); i < n; i++ {
 		if p.isExported(t.Method(i).Name()) {
-			p.visit(t.Method(i).Type())
+			p.visit(t.Method(i).Type(), depth)
 		}
 	}
 }
 
-func (p *Packagist) visitStruct(t *types.Struct) {
+func (p *Packagist) visitStruct(t *types.Struct, depth int) {
 	for i := 0; i < t.NumFields(); i++ {
 		if p.isExported(t.Field(i).Name()) {
-			p.visit(t.Field(i).Type())
+			p.visit(t.Field(i).Type(), depth)
 		}
 	}
 }
 
+// isCollectable reports whether a type reached after the given number
+// of hops from the filtered types is within the configured boundaries.
+func (p *Packagist) isCollectable(t *types.Named, depth int) bool {
+	if depth == 0 {
+		return true
+	}
+	if p.MaxDepth > 0 && depth > p.MaxDepth {
+		return false
+	}
+	if pkg := t.Obj().Pkg(); pkg != nil {
+		return !p.StopFilterFunc(pkg.Path())
+	}
+	return true
+}
+
 func (p *Packagist) isExported(s string) bool {
 	if p.IncludeUnexported {
 		return true
@@ -4,9 +4,13 @@ package internal
 
 import (
 	"errors"
+	gotypes "go/types"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/dtgorski/typex/internal/testdata/p1"
+	"github.com/dtgorski/typex/internal/testdata/p5"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -55,6 +59,46 @@ func TestInspector_Inspect_3(t *testing.T) {
 	}
 }
 
+func TestInspector_Inspect_Partial(t *testing.T) {
+	b := gotypes.NewPackage("b", "b")
+	obj := gotypes.NewTypeName(0, b, "T", nil)
+	gotypes.NewNamed(obj, gotypes.Typ[gotypes.Int], nil)
+	b.Scope().Insert(obj)
+
+	errs := []packages.Error{
+		{Pos: "a.go:1:2", Msg: "x", Kind: packages.TypeError},
+		{Msg: "y", Kind: packages.ListError},
+	}
+	pkgs := []*packages.Package{
+		{PkgPath: "a", Errors: errs},
+		{PkgPath: "a", Errors: errs[:1]},
+		{PkgPath: "b", Types: b},
+	}
+	loader := func(c *packages.Config, p ...string) ([]*packages.Package, error) {
+		return pkgs, nil
+	}
+	pac := Packagist{
+		PackageLoaderFunc: loader,
+		Partial:           true,
+	}
+	types, err := pac.Inspect(".")
+	if err != nil || len(types) != 1 || types["b.T"] == nil {
+		t.Errorf("unexpected %v %v", types, err)
+	}
+	want := Diagnostics{
+		{Package: "a", Position: "a.go:1:2", Kind: "type", Message: "x"},
+		{Package: "a", Position: "-", Kind: "list", Message: "y"},
+	}
+	if diags := pac.Diagnostics(); !reflect.DeepEqual(diags, want) {
+		t.Errorf("unexpected %v", diags)
+	}
+
+	pac.Partial = false
+	if _, err := pac.Inspect("."); !reflect.DeepEqual(err, want) {
+		t.Errorf("unexpected %v", err)
+	}
+}
+
 type TestType struct {
 	_      [10]chan bool
 	Public map[int]int
@@ -81,3 +125,86 @@ func TestInspector_Inspect_4(t *testing.T) {
 		return
 	}
 }
+
+func TestInspector_Inspect_5(t *testing.T) {
+	pkgPath := reflect.TypeOf(p1.T{}).PkgPath()
+
+	pac := Packagist{
+		PathFilterFunc: CreatePathFilterFunc([]string{`p1\.T$`}, nil),
+		MaxDepth:       1,
+	}
+	types, err := pac.Inspect(pkgPath)
+	if err != nil {
+		t.Error("unexpected")
+		return
+	}
+	if types[pkgPath+".D"] == nil || types["time.Time"] != nil {
+		t.Error("unexpected")
+	}
+}
+
+func TestInspector_Inspect_6(t *testing.T) {
+	pkgPath := reflect.TypeOf(p1.T{}).PkgPath()
+
+	pac := Packagist{
+		PathFilterFunc: CreatePathFilterFunc([]string{`p1\.T$`}, nil),
+		StopFilterFunc: CreatePathFilterFunc([]string{`p2`}, nil),
+	}
+	types, err := pac.Inspect(pkgPath)
+	if err != nil {
+		t.Error("unexpected")
+		return
+	}
+	for name := range types {
+		if strings.Contains(name, "/p2") {
+			t.Errorf("unexpected %s", name)
+		}
+	}
+	if types[pkgPath+".T"] == nil || types["time.Time"] == nil {
+		t.Error("unexpected")
+	}
+}
+
+func TestInspector_Position(t *testing.T) {
+	pkgPath := reflect.TypeOf(p1.T{}).PkgPath()
+
+	pac := Packagist{
+		PathFilterFunc: CreatePathFilterFunc([]string{`p1\.A$`}, nil),
+	}
+	types, err := pac.Inspect(pkgPath)
+	if err != nil {
+		t.Error("unexpected")
+		return
+	}
+	named := types[pkgPath+".A"].(*gotypes.Named)
+	if pos := pac.Position(named); pos != "internal/testdata/p1/types.go:13" {
+		t.Errorf("unexpected %s", pos)
+	}
+}
+
+func TestInspector_Doc(t *testing.T) {
+	pkgPath := reflect.TypeOf(p5.Order{}).PkgPath()
+
+	pac := Packagist{
+		PathFilterFunc: CreatePathFilterFunc([]string{`p5\.Order$`}, nil),
+	}
+	types, err := pac.Inspect(pkgPath)
+	if err != nil {
+		t.Error("unexpected")
+		return
+	}
+	named := types[pkgPath+".Order"].(*gotypes.Named)
+	scope := named.Obj().Pkg().Scope()
+
+	for obj, doc := range map[gotypes.Object]string{
+		named.Obj(): "Order is a customer order.",
+		named.Underlying().(*gotypes.Struct).Field(0): "ID is the order number.",
+		named.Underlying().(*gotypes.Struct).Field(1): "",
+		scope.Lookup("StatusOpen"):                    "StatusOpen awaits fulfillment.",
+		scope.Lookup("StatusClosed"):                  "",
+	} {
+		if got := pac.Doc(obj); got != doc {
+			t.Errorf("unexpected %q", got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package proto
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+type (
+	// Lock maps fully qualified message names to the numbers
+	// assigned to their fields. Numbers of removed fields are
+	// kept in the lock, so they are never assigned again.
+	Lock map[string]map[string]int
+
+	reservation struct {
+		Name   string
+		Number int
+	}
+)
+
+// ReadLock reads a lock file. A missing file yields an empty Lock.
+func ReadLock(name string) (Lock, error) {
+	lock := make(Lock)
+	if name == "" {
+		return lock, nil
+	}
+	data, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// Write stores the lock in a file.
+func (l Lock) Write(name string) error {
+	data, err := json.MarshalIndent(l, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, append(data, '\n'), 0644)
+}
+
+// number returns the number of a message field. New fields get
+// the next number above the highest one ever assigned.
+func (l Lock) number(msg, field string) int {
+	fields, ok := l[msg]
+	if !ok {
+		fields = make(map[string]int)
+		l[msg] = fields
+	}
+	if n, ok := fields[field]; ok {
+		return n
+	}
+	n := 1
+	for _, i := range fields {
+		if i >= n {
+			n = i + 1
+		}
+	}
+	if n >= 19000 && n <= 19999 {
+		n = 20000 // reserved for the protobuf implementation
+	}
+	fields[field] = n
+	return n
+}
+
+// reserved returns the locked fields of a message, which are
+// not contained in the used set, ordered by number.
+func (l Lock) reserved(msg string, used map[string]bool) []reservation {
+	res := make([]reservation, 0)
+	for name, n := range l[msg] {
+		if !used[name] {
+			res = append(res, reservation{name, n})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Number < res[j].Number
+	})
+	return res
+}
@@ -0,0 +1,446 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package proto
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
+)
+
+type (
+	// TypeRender renders Go types as Protocol Buffers (proto3)
+	// declarations, one .proto file per Go package.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+		TagKey            string
+		Lock              Lock
+		Ranking           typex.Ranking
+		SortFields        bool
+	}
+
+	protoFile struct {
+		goPath  string
+		path    string
+		pkg     string
+		decls   []string
+		imports map[string]bool
+	}
+
+	message struct {
+		file   *protoFile
+		decl   *model.Decl
+		name   string
+		indent int
+		used   map[string]bool
+		nested map[*model.Decl]bool
+		fields bytes.Buffer
+	}
+
+	enumValue struct {
+		name  string
+		value int64
+	}
+)
+
+// Render converts a TypeMap to .proto files. Structs are rendered
+// as messages, enumerations of integers fitting into int32 as enums.
+// Other named types have no counterpart in the schema, references to
+// them resolve to the aliased type. Fields of types without a valid
+// mapping are left as comment.
+func (r *TypeRender) Render(m typex.TypeMap) []typex.File {
+	if r.Lock == nil {
+		r.Lock = make(Lock)
+	}
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		Ranking:           r.Ranking,
+		SortFields:        r.SortFields,
+	}
+	mod := b.Build(m)
+	out := make([]typex.File, 0)
+
+	for _, p := range mod.Packages() {
+		decls := mod.Package(p)
+		f := &protoFile{
+			goPath:  decls[0].Origin.(*types.Named).Obj().Pkg().Path(),
+			path:    p + ".proto",
+			pkg:     packageName(p),
+			imports: make(map[string]bool),
+		}
+		for _, d := range decls {
+			switch d.Kind {
+			case model.Struct:
+				buf := bytes.Buffer{}
+				r.writeMessage(&buf, &message{file: f, name: f.pkg}, d)
+				f.decls = append(f.decls, buf.String())
+			case model.Enum:
+				if values := enumValues(d); len(values) > 0 {
+					f.decls = append(f.decls, r.enum(d.Name, values))
+				}
+			case model.Alias:
+				for _, n := range d.Nested {
+					buf := bytes.Buffer{}
+					r.writeMessage(&buf, &message{file: f, name: f.pkg}, n)
+					f.decls = append(f.decls, buf.String())
+				}
+			}
+		}
+		if len(f.decls) > 0 {
+			out = append(out, typex.File{Path: f.path, Content: f.String()})
+		}
+	}
+	return out
+}
+
+func (f *protoFile) String() string {
+	buf := bytes.Buffer{}
+	buf.WriteString("syntax = \"proto3\";\n\n")
+	buf.WriteString("package " + f.pkg + ";\n\n")
+
+	imports := make([]string, 0, len(f.imports))
+	for i := range f.imports {
+		imports = append(imports, i)
+	}
+	sort.Strings(imports)
+	for _, i := range imports {
+		buf.WriteString("import \"" + i + "\";\n")
+	}
+	if len(imports) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("option go_package = \"" + f.goPath + "\";\n")
+
+	for _, d := range f.decls {
+		buf.WriteString("\n" + d)
+	}
+	return buf.String()
+}
+
+func (f *protoFile) snapshot() map[string]bool {
+	imports := make(map[string]bool, len(f.imports))
+	for k, v := range f.imports {
+		imports[k] = v
+	}
+	return imports
+}
+
+func (m *message) snapshot() map[*model.Decl]bool {
+	nested := make(map[*model.Decl]bool, len(m.nested))
+	for k, v := range m.nested {
+		nested[k] = v
+	}
+	return nested
+}
+
+// writeMessage writes the message of a struct. The messages of
+// anonymous structs are nested in the message referring to them.
+func (r *TypeRender) writeMessage(w *bytes.Buffer, parent *message, d *model.Decl) {
+	name := d.Name
+	if parent.decl == nil {
+		name = d.Flat()
+	}
+	msg := &message{
+		file:   parent.file,
+		decl:   d,
+		name:   parent.name + "." + name,
+		used:   make(map[string]bool),
+		nested: make(map[*model.Decl]bool),
+	}
+	if parent.decl != nil {
+		msg.indent = parent.indent + 1
+	}
+	r.writeFields(msg, d)
+
+	nested := bytes.Buffer{}
+	for _, n := range d.Nested {
+		if msg.nested[n] {
+			r.writeMessage(&nested, msg, n)
+		}
+	}
+	pad := strings.Repeat("    ", msg.indent)
+	res := r.Lock.reserved(msg.name, msg.used)
+
+	if len(res) == 0 && nested.Len() == 0 && msg.fields.Len() == 0 {
+		r.write(w, "%smessage %s {}\n", pad, name)
+		return
+	}
+	r.write(w, "%smessage %s {\n", pad, name)
+
+	if len(res) > 0 {
+		nums, names := make([]string, 0, len(res)), make([]string, 0, len(res))
+		for _, x := range res {
+			nums = append(nums, fmt.Sprint(x.Number))
+			names = append(names, `"`+x.Name+`"`)
+		}
+		r.write(w, "%s    reserved %s;\n", pad, strings.Join(nums, ", "))
+		r.write(w, "%s    reserved %s;\n", pad, strings.Join(names, ", "))
+	}
+	w.Write(nested.Bytes())
+	w.Write(msg.fields.Bytes())
+	r.write(w, "%s}\n", pad)
+}
+
+func (r *TypeRender) writeFields(msg *message, d *model.Decl) {
+	pad := strings.Repeat("    ", msg.indent+1)
+
+	for _, fld := range d.Fields {
+		imports, nested := msg.file.snapshot(), msg.snapshot()
+		label, typ, ok := r.resolve(msg, fld.Type)
+		if !ok {
+			msg.file.imports, msg.nested = imports, nested
+			r.write(&msg.fields, "%s// %s: unsupported type %s\n", pad, fld.Name, typex.Abbreviate(fld.Origin.Type()))
+			continue
+		}
+		name := identifier(typex.SnakeCase(fld.Name))
+		msg.used[name] = true
+
+		if label != "" {
+			label += " "
+		}
+		r.write(&msg.fields, "%s%s%s %s = %d", pad, label, typ, name, r.Lock.number(msg.name, name))
+		if fld.Wire != jsonName(name) {
+			r.write(&msg.fields, " [json_name = %q]", fld.Wire)
+		}
+		r.write(&msg.fields, ";\n")
+	}
+}
+
+// resolve returns the field label and the field type of a type
+// reference. Nested messages are marked as used by the field.
+func (r *TypeRender) resolve(msg *message, t *model.Type) (label, typ string, ok bool) {
+	t = model.Unalias(t, false)
+	if wk := wellKnown(t); wk != "" {
+		msg.file.imports[wellKnownImport(wk)] = true
+		typ, ok = wk, true
+	} else {
+		typ, ok = r.resolveType(msg, t)
+	}
+	if ok && t.Nullable && !isMap(typ) && !strings.HasPrefix(typ, "repeated ") {
+		label = "optional"
+	}
+	if strings.HasPrefix(typ, "repeated ") {
+		label, typ = "repeated", strings.TrimPrefix(typ, "repeated ")
+	}
+	return label, typ, ok
+}
+
+func (r *TypeRender) resolveType(msg *message, t *model.Type) (string, bool) {
+	switch t.Kind {
+	case model.Bool, model.Int, model.Uint, model.Float, model.String:
+		return scalar(t), true
+
+	case model.Bytes:
+		return "bytes", true
+
+	case model.List:
+		label, typ, ok := r.resolve(msg, t.Elem)
+		if !ok || label == "repeated" || isMap(typ) {
+			return "", false
+		}
+		return "repeated " + typ, true
+
+	case model.Map:
+		k, ok := mapKey(t.Key)
+		if !ok {
+			return "", false
+		}
+		label, v, ok := r.resolve(msg, t.Elem)
+		if !ok || label == "repeated" || isMap(v) {
+			return "", false
+		}
+		return "map<" + k + ", " + v + ">", true
+
+	case model.Ref:
+		if t.Decl.Kind == model.Enum && len(enumValues(t.Decl)) == 0 {
+			return scalar(t.Decl.Type), true
+		}
+		if t.Decl.Parent == msg.decl {
+			msg.nested[t.Decl] = true
+			return t.Decl.Name, true
+		}
+		return r.reference(msg.file, t.Decl), true
+	}
+	return "", false
+}
+
+// reference returns the name of a message or enum, qualified with
+// the package name when declared in another file, which is imported.
+func (r *TypeRender) reference(f *protoFile, d *model.Decl) string {
+	p := d.Path + ".proto"
+	if p == f.path {
+		return d.Qualified(".")
+	}
+	f.imports[p] = true
+	return packageName(d.Path) + "." + d.Qualified(".")
+}
+
+// enumValues returns the values of an integer enumeration fitting
+// into int32, ordered by value and declaration. A zero value is
+// added, if missing, as required by proto3.
+func enumValues(d *model.Decl) []enumValue {
+	if d.Type.Kind != model.Int && d.Type.Kind != model.Uint {
+		return nil
+	}
+	for _, c := range d.Values {
+		if v, ok := constant.Int64Val(c.Value); !ok || v < -1<<31 || v > 1<<31-1 {
+			return nil
+		}
+	}
+
+	prefix := strings.ToUpper(typex.SnakeCase(d.Name))
+	values := make([]enumValue, 0, len(d.Values)+1)
+	zero := false
+
+	for _, c := range d.Values {
+		v, _ := constant.Int64Val(c.Value)
+		values = append(values, enumValue{identifier(prefix + "_" + typex.EnumName("", c.Name)), v})
+		zero = zero || v == 0
+	}
+	if !zero {
+		values = append(values, enumValue{prefix + "_UNSPECIFIED", 0})
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].value < values[j].value
+	})
+	return values
+}
+
+func (r *TypeRender) enum(name string, values []enumValue) string {
+	buf := bytes.Buffer{}
+	r.write(&buf, "enum %s {\n", name)
+
+	for i := 1; i < len(values); i++ {
+		if values[i].value == values[i-1].value {
+			r.write(&buf, "    option allow_alias = true;\n")
+			break
+		}
+	}
+	for _, v := range values {
+		r.write(&buf, "    %s = %d;\n", v.name, v.value)
+	}
+	r.write(&buf, "}\n")
+	return buf.String()
+}
+
+func (TypeRender) write(w *bytes.Buffer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
+
+// packageName derives a proto package name from an import path.
+func packageName(path string) string {
+	parts := make([]string, 0)
+	for _, s := range strings.Split(path, "/") {
+		if s == "" {
+			continue
+		}
+		parts = append(parts, strings.Map(func(c rune) rune {
+			if c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+				return unicode.ToLower(c)
+			}
+			return '_'
+		}, s))
+	}
+	return strings.Join(parts, ".")
+}
+
+func scalar(t *model.Type) string {
+	switch t.Kind {
+	case model.Bool:
+		return "bool"
+	case model.String:
+		return "string"
+	case model.Int:
+		if t.Bits == 64 {
+			return "int64"
+		}
+		return "int32"
+	case model.Uint:
+		if t.Bits == 64 {
+			return "uint64"
+		}
+		return "uint32"
+	case model.Float:
+		if t.Bits == 32 {
+			return "float"
+		}
+		return "double"
+	}
+	return ""
+}
+
+// mapKey returns the scalar of a valid map key, i.e. any integral
+// or string type. Enums and floating point types are not allowed.
+func mapKey(t *model.Type) (string, bool) {
+	switch t = model.Unalias(t, true); {
+	case t.Nullable:
+	case t.Kind == model.Bool, t.Kind == model.Int, t.Kind == model.Uint, t.Kind == model.String:
+		return scalar(t), true
+	}
+	return "", false
+}
+
+// wellKnown returns the well-known type of a type reference, if any.
+func wellKnown(t *model.Type) string {
+	if t.Kind == model.Time {
+		return "google.protobuf.Timestamp"
+	}
+	nt, ok := t.Origin.(*types.Named)
+	if ok && nt.Obj().Pkg() != nil && nt.Obj().Pkg().Path() == "time" && nt.Obj().Name() == "Duration" {
+		return "google.protobuf.Duration"
+	}
+	return ""
+}
+
+func wellKnownImport(s string) string {
+	return "google/protobuf/" + strings.ToLower(strings.TrimPrefix(s, "google.protobuf.")) + ".proto"
+}
+
+func isMap(typ string) bool {
+	return strings.HasPrefix(typ, "map<")
+}
+
+// jsonName returns the JSON name protoc derives from a field name.
+func jsonName(s string) string {
+	b, up := strings.Builder{}, false
+	for _, c := range s {
+		switch {
+		case c == '_':
+			up = true
+		case up:
+			b.WriteRune(unicode.ToUpper(c))
+			up = false
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// identifier returns a valid name of a field or an enum value, limited
+// to ASCII letters, digits and underscores.
+func identifier(s string) string {
+	return (typex.IdentifierPolicy{IsIdentRune: typex.IsASCIIIdent}).Identifier(s)
+}
+
+// Reserved reports whether a type name or a package path segment
+// is a keyword or clashes with a name the rendered schema relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "bool", "bytes", "double", "fixed32", "fixed64", "float", "google",
+		"int32", "int64", "sfixed32", "sfixed64", "sint32", "sint64",
+		"string", "uint32", "uint64":
+		return true
+	}
+	return false
+}
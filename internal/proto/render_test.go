@@ -0,0 +1,96 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package proto
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func inspect(t *testing.T) typex.TypeMap {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p5\.`}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(p5.Order{}).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+	return types
+}
+
+func render(tr TypeRender, types typex.TypeMap, path string) string {
+	tr.PathReplaceFunc = typex.CreatePathReplaceFunc([]string{".*/testdata:"})
+	for _, f := range tr.Render(types) {
+		if f.Path == path {
+			return f.Content
+		}
+	}
+	return ""
+}
+
+func TestTypeRender_Render(t *testing.T) {
+	want := `syntax = "proto3";
+
+package p5;
+
+import "google/protobuf/timestamp.proto";
+import "p2.proto";
+
+option go_package = "github.com/dtgorski/typex/internal/testdata/p5";
+
+message Item {
+    string sku = 1;
+    int32 quantity = 2;
+}
+
+enum Level {
+    option allow_alias = true;
+    LEVEL_LOW = 0;
+    LEVEL_HIGH = 1;
+    LEVEL_MAX = 1;
+}
+
+message Order {
+    string id = 1;
+    Status status = 2;
+    optional Level level = 3;
+    repeated Item items = 4;
+    map<string, string> labels = 5;
+    google.protobuf.Timestamp created = 6;
+    optional string note = 7;
+    optional p2.S ref = 8;
+}
+
+enum Status {
+    STATUS_UNSPECIFIED = 0;
+    STATUS_OPEN = 1;
+    STATUS_CLOSED = 2;
+}
+`
+	if got := render(TypeRender{}, inspect(t), "p5.proto"); got != want {
+		t.Errorf("unexpected\n%s", got)
+	}
+}
+
+func TestTypeRender_Lock(t *testing.T) {
+	lock := Lock{"p5.Item": {"name": 1, "quantity": 2, "sku": 5}}
+
+	want := "message Item {\n" +
+		"    reserved 1;\n" +
+		"    reserved \"name\";\n" +
+		"    string sku = 5;\n" +
+		"    int32 quantity = 2;\n" +
+		"}\n"
+
+	got := render(TypeRender{Lock: lock}, inspect(t), "p5.proto")
+	if !strings.Contains(got, want) {
+		t.Errorf("unexpected\n%s", got)
+	}
+	if n := lock.number("p5.Item", "price"); n != 6 {
+		t.Errorf("unexpected %d", n)
+	}
+}
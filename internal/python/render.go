@@ -0,0 +1,316 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package python
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
+)
+
+type (
+	// TypeRender renders Go types as Python pydantic v2 models
+	// or as standard library dataclasses, one module per package.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+		TagKey            string
+		Dataclasses       bool
+		Ranking           typex.Ranking
+		SortFields        bool
+	}
+
+	module struct {
+		name     string
+		enums    []string
+		classes  []string
+		declared map[*model.Decl]bool
+		imports  map[string]bool
+		typing   map[string]bool
+		enumBase map[string]bool
+		datetime bool
+	}
+)
+
+// Render converts a TypeMap to Python modules. Structs are rendered
+// as classes, anonymous structs as classes named after the enclosing
+// class and the field, enumerations as enums. Aliases have no
+// counterpart, references to them resolve to the aliased type.
+// References to classes declared later in a module, and to the class
+// itself, are forward references.
+func (r *TypeRender) Render(m typex.TypeMap) []typex.File {
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		Ranking:           r.Ranking,
+		SortFields:        r.SortFields,
+	}
+	mod := b.Build(m)
+	files := make([]typex.File, 0)
+
+	for _, p := range mod.Packages() {
+		pm := &module{
+			name:     moduleName(p),
+			declared: make(map[*model.Decl]bool),
+			imports:  make(map[string]bool),
+			typing:   make(map[string]bool),
+			enumBase: make(map[string]bool),
+		}
+		for _, d := range mod.Package(p) {
+			if d.Kind == model.Enum {
+				pm.enums = append(pm.enums, r.enum(pm, d))
+			}
+		}
+		for _, d := range mod.Package(p) {
+			switch d.Kind {
+			case model.Struct:
+				r.class(pm, d)
+			case model.Alias:
+				for _, n := range d.Nested {
+					r.class(pm, n)
+				}
+			}
+		}
+		if len(pm.enums)+len(pm.classes) == 0 {
+			continue
+		}
+		file := strings.ReplaceAll(pm.name, ".", "/") + "/__init__.py"
+		files = append(files, typex.File{Path: file, Content: r.source(pm)})
+	}
+	return files
+}
+
+func (r *TypeRender) source(mod *module) string {
+	buf := bytes.Buffer{}
+
+	std := make([]string, 0)
+	if mod.datetime {
+		std = append(std, "from datetime import datetime")
+	}
+	if len(mod.enumBase) > 0 {
+		std = append(std, "from enum import "+strings.Join(sortedKeys(mod.enumBase), ", "))
+	}
+	if len(mod.typing) > 0 {
+		std = append(std, "from typing import "+strings.Join(sortedKeys(mod.typing), ", "))
+	}
+	if len(mod.classes) > 0 && r.Dataclasses {
+		std = append([]string{"from dataclasses import dataclass, field"}, std...)
+	}
+	if len(std) > 0 {
+		r.write(&buf, "%s\n\n", strings.Join(std, "\n"))
+	}
+	if len(mod.classes) > 0 && !r.Dataclasses {
+		r.write(&buf, "from pydantic import BaseModel, ConfigDict, Field\n\n")
+	}
+
+	local := make([]string, 0, len(mod.imports))
+	for _, i := range sortedKeys(mod.imports) {
+		local = append(local, "import "+i)
+	}
+	if len(local) > 0 {
+		r.write(&buf, "%s\n\n", strings.Join(local, "\n"))
+	}
+	decls := append(mod.enums, mod.classes...)
+	r.write(&buf, "\n%s", strings.Join(decls, "\n\n"))
+	return buf.String()
+}
+
+// class declares the classes of the anonymous structs of a struct,
+// followed by the class of the struct.
+func (r *TypeRender) class(mod *module, d *model.Decl) {
+	for _, n := range d.Nested {
+		r.class(mod, n)
+	}
+	fields := bytes.Buffer{}
+	r.writeFields(mod, &fields, d)
+
+	buf := bytes.Buffer{}
+	name := identifier(d.Flat())
+	if r.Dataclasses {
+		r.write(&buf, "@dataclass(kw_only=True)\nclass %s:\n", name)
+	} else {
+		r.write(&buf, "class %s(BaseModel):\n", name)
+		r.write(&buf, "    model_config = ConfigDict(populate_by_name=True)\n")
+		if fields.Len() > 0 {
+			r.write(&buf, "\n")
+		}
+	}
+	if fields.Len() == 0 && r.Dataclasses {
+		r.write(&buf, "    pass\n")
+	}
+	buf.Write(fields.Bytes())
+
+	mod.classes = append(mod.classes, buf.String())
+	mod.declared[d] = true
+}
+
+func (r *TypeRender) writeFields(mod *module, w *bytes.Buffer, d *model.Decl) {
+	for _, fld := range d.Fields {
+		typ := r.typeOf(mod, fld.Type)
+		optional := fld.Optional || model.Unalias(fld.Type, false).Nullable
+
+		name := identifier(typex.SnakeCase(fld.Name))
+		alias := ""
+		if fld.Wire != name {
+			alias = fld.Wire
+		}
+		if optional {
+			mod.typing["Optional"] = true
+			typ = "Optional[" + typ + "]"
+		}
+		r.write(w, "    %s: %s%s\n", name, typ, r.fieldDefault(optional, alias))
+	}
+}
+
+func (r *TypeRender) fieldDefault(optional bool, alias string) string {
+	switch {
+	case alias == "" && optional:
+		return " = None"
+	case alias == "":
+		return ""
+	case r.Dataclasses && optional:
+		return fmt.Sprintf(" = field(default=None, metadata={%q: %q})", r.tagKey(), alias)
+	case r.Dataclasses:
+		return fmt.Sprintf(" = field(metadata={%q: %q})", r.tagKey(), alias)
+	case optional:
+		return fmt.Sprintf(" = Field(default=None, alias=%q)", alias)
+	}
+	return fmt.Sprintf(" = Field(alias=%q)", alias)
+}
+
+// typeOf returns the Python type annotation of a type reference.
+func (r *TypeRender) typeOf(mod *module, t *model.Type) string {
+	t = model.Unalias(t, false)
+
+	switch t.Kind {
+	case model.Bool:
+		return "bool"
+	case model.Int, model.Uint:
+		return "int"
+	case model.Float:
+		return "float"
+	case model.String, model.Bytes:
+		return "str" // encoding/json encodes []byte as base64 string
+	case model.Time:
+		mod.datetime = true
+		return "datetime"
+	case model.List:
+		return "list[" + r.typeOf(mod, t.Elem) + "]"
+	case model.Map:
+		return "dict[" + r.typeOf(mod, t.Key) + ", " + r.typeOf(mod, t.Elem) + "]"
+	case model.Ref:
+		return r.reference(mod, t.Decl)
+	}
+	mod.typing["Any"] = true
+	return "Any"
+}
+
+// reference returns the name of a class or enum, qualified with the
+// module name when declared in another module, which is imported.
+// Classes of the same module not declared yet are forward references.
+func (r *TypeRender) reference(mod *module, d *model.Decl) string {
+	name := identifier(d.Flat())
+	if m := moduleName(d.Path); m != mod.name {
+		mod.imports[m] = true
+		return m + "." + name
+	}
+	if d.Kind == model.Enum || mod.declared[d] {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func (r *TypeRender) enum(mod *module, d *model.Decl) string {
+	buf := bytes.Buffer{}
+	name := identifier(d.Name)
+
+	if d.Type.Kind == model.String {
+		mod.enumBase["Enum"] = true
+		r.write(&buf, "class %s(str, Enum):\n", name)
+	} else {
+		mod.enumBase["IntEnum"] = true
+		r.write(&buf, "class %s(IntEnum):\n", name)
+	}
+	seen := make(map[string]bool)
+	for _, v := range d.Values {
+		n := identifier(typex.EnumName("", v.Name))
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		if v.Value.Kind() == constant.String {
+			r.write(&buf, "    %s = %s\n", n, strconv.Quote(constant.StringVal(v.Value)))
+		} else {
+			r.write(&buf, "    %s = %s\n", n, v.Value.ExactString())
+		}
+	}
+	return buf.String()
+}
+
+func (r *TypeRender) tagKey() string {
+	if r.TagKey != "" {
+		return r.TagKey
+	}
+	return typex.DefaultTagKey
+}
+
+func (TypeRender) write(w *bytes.Buffer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
+
+// moduleName derives a dotted Python module name from an import path.
+func moduleName(path string) string {
+	parts := make([]string, 0)
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			parts = append(parts, identifier(strings.Map(func(c rune) rune {
+				if c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+					return c
+				}
+				return '_'
+			}, s)))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// identifier appends an underscore to Python keywords.
+func identifier(s string) string {
+	switch s {
+	case "False", "None", "True", "and", "as", "assert", "async", "await",
+		"break", "class", "continue", "def", "del", "elif", "else", "except",
+		"finally", "for", "from", "global", "if", "import", "in", "is",
+		"lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try",
+		"while", "with", "yield":
+		return s + "_"
+	}
+	return s
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Reserved reports whether a type name or a package path segment
+// is a keyword or clashes with a name the rendered Python code relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "Any", "BaseModel", "ConfigDict", "Enum", "Field", "IntEnum",
+		"Optional", "dataclass", "datetime", "field":
+		return true
+	}
+	return identifier(s) != s
+}
@@ -0,0 +1,89 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package python
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func render(t *testing.T, tr TypeRender, path string) string {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p5\.`}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(p5.Order{}).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+	tr.PathReplaceFunc = typex.CreatePathReplaceFunc([]string{".*/testdata:"})
+	for _, f := range tr.Render(types) {
+		if f.Path == path {
+			return f.Content
+		}
+	}
+	return ""
+}
+
+func TestTypeRender_Render(t *testing.T) {
+	want := `from datetime import datetime
+from enum import IntEnum
+from typing import Optional
+
+from pydantic import BaseModel, ConfigDict, Field
+
+import p2
+
+
+class Level(IntEnum):
+    LOW = 0
+    HIGH = 1
+    MAX = 1
+
+
+class Status(IntEnum):
+    OPEN = 1
+    CLOSED = 2
+
+
+class Item(BaseModel):
+    model_config = ConfigDict(populate_by_name=True)
+
+    sku: str
+    quantity: int
+
+
+class Order(BaseModel):
+    model_config = ConfigDict(populate_by_name=True)
+
+    id: str
+    status: Status
+    level: Optional[Level] = None
+    items: list[Item]
+    labels: dict[str, str]
+    created: datetime
+    note: Optional[str] = None
+    ref: Optional[p2.S] = None
+`
+	if got := render(t, TypeRender{}, "p5/__init__.py"); got != want {
+		t.Errorf("unexpected\n%s", got)
+	}
+}
+
+func TestTypeRender_Dataclasses(t *testing.T) {
+	got := render(t, TypeRender{Dataclasses: true}, "p2/__init__.py")
+
+	for _, want := range []string{
+		"from dataclasses import dataclass, field\n",
+		"@dataclass(kw_only=True)\nclass S:\n",
+		"    fn: Optional[Any] = field(default=None, metadata={\"json\": \"Fn\"})\n",
+		"    types: Optional[\"T\"] = field(default=None, metadata={\"json\": \"Types\"})\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("unexpected\n%s", got)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+type (
+	// TypeFilterFunc is a matching function for declared types.
+	TypeFilterFunc func(TypeInfo) bool
+
+	// TypeInfo describes a declared type for a TypeFilterFunc.
+	TypeInfo struct {
+		Object *types.TypeName
+		Doc    string
+
+		// Lookup resolves a qualified type name, e.g. "io.Reader",
+		// within the inspected packages and their dependencies.
+		Lookup func(name string) types.Type
+	}
+
+	queryParser struct {
+		tokens []string
+		pos    int
+	}
+)
+
+// ParseQuery converts a query expression to a TypeFilterFunc. The
+// expression consists of predicates in the form of key:value, which
+// can be combined with AND, OR, NOT and parentheses. Juxtaposed
+// predicates aggregate to an AND query. Available predicates are:
+//
+//	kind:<kind>          struct, interface, map, slice, array, chan, func, pointer, basic
+//	name:<name>          type name expression
+//	pkg:<path>           package import path expression
+//	has-field:<name>     struct has a (promoted) field of the given name
+//	tag:<key>            struct has a field tagged with the given key
+//	implements:<name>    type or pointer to type implements the interface
+//	doc:<text>           doc comment expression, e.g. doc:@deprecated
+//
+// Expressions can be a plain text or a regular expression.
+func ParseQuery(s string) (TypeFilterFunc, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("query: empty")
+	}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("query: unexpected %q", p.tokens[p.pos])
+	}
+	return f, nil
+}
+
+func (p *queryParser) parseOr() (TypeFilterFunc, error) {
+	lhs, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR", "||") {
+		rhs, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		lhs = or(lhs, rhs)
+	}
+	return lhs, nil
+}
+
+func (p *queryParser) parseAnd() (TypeFilterFunc, error) {
+	lhs, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if !p.accept("AND", "&&") {
+			if p.peek() == "" || p.peek() == ")" || p.is("OR", "||") {
+				return lhs, nil
+			}
+		}
+		rhs, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		lhs = and(lhs, rhs)
+	}
+}
+
+func (p *queryParser) parseNot() (TypeFilterFunc, error) {
+	if p.accept("NOT", "!") {
+		f, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(t TypeInfo) bool { return !f(t) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (TypeFilterFunc, error) {
+	tok := p.peek()
+	switch tok {
+	case "":
+		return nil, fmt.Errorf("query: unexpected end")
+	case ")":
+		return nil, fmt.Errorf("query: unexpected %q", tok)
+	case "(":
+		p.pos++
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("query: missing %q", ")")
+		}
+		return f, nil
+	}
+	p.pos++
+	return predicate(tok)
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) is(ops ...string) bool {
+	tok := p.peek()
+	for _, op := range ops {
+		if strings.EqualFold(tok, op) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *queryParser) accept(ops ...string) bool {
+	if p.is(ops...) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func predicate(tok string) (TypeFilterFunc, error) {
+	i := strings.Index(tok, ":")
+	if i < 1 || i == len(tok)-1 {
+		return nil, fmt.Errorf("query: invalid predicate %q", tok)
+	}
+	key, val := tok[:i], strings.Trim(tok[i+1:], `"`)
+
+	switch key {
+	case "kind":
+		switch val {
+		case "struct", "interface", "map", "slice", "array", "chan", "func", "pointer", "basic":
+		default:
+			return nil, fmt.Errorf("query: invalid kind %q", val)
+		}
+		return func(t TypeInfo) bool {
+			return KindOf(t.Object.Type().Underlying()) == val
+		}, nil
+
+	case "name":
+		re := compilePattern(val)
+		return func(t TypeInfo) bool {
+			return re.MatchString(t.Object.Name())
+		}, nil
+
+	case "pkg":
+		re := compilePattern(val)
+		return func(t TypeInfo) bool {
+			return t.Object.Pkg() != nil && re.MatchString(t.Object.Pkg().Path())
+		}, nil
+
+	case "has-field":
+		return func(t TypeInfo) bool {
+			obj, _, _ := types.LookupFieldOrMethod(t.Object.Type(), true, t.Object.Pkg(), val)
+			v, ok := obj.(*types.Var)
+			return ok && v.IsField()
+		}, nil
+
+	case "tag":
+		return func(t TypeInfo) bool {
+			s, ok := t.Object.Type().Underlying().(*types.Struct)
+			for i := 0; ok && i < s.NumFields(); i++ {
+				if _, has := reflect.StructTag(s.Tag(i)).Lookup(val); has {
+					return true
+				}
+			}
+			return false
+		}, nil
+
+	case "implements":
+		return func(t TypeInfo) bool {
+			if t.Lookup == nil {
+				return false
+			}
+			typ, target := t.Object.Type(), t.Lookup(val)
+			if target == nil || types.Identical(typ, target) {
+				return false
+			}
+			iface, ok := target.Underlying().(*types.Interface)
+			if !ok {
+				return false
+			}
+			return types.Implements(typ, iface) || types.Implements(types.NewPointer(typ), iface)
+		}, nil
+
+	case "doc":
+		re := compilePattern(val)
+		return func(t TypeInfo) bool {
+			return re.MatchString(t.Doc)
+		}, nil
+	}
+	return nil, fmt.Errorf("query: unknown predicate %q", key)
+}
+
+func tokenize(s string) ([]string, error) {
+	tokens := make([]string, 0)
+	buf, quoted := strings.Builder{}, false
+
+	flush := func() {
+		if buf.Len() > 0 {
+			tokens = append(tokens, buf.String())
+			buf.Reset()
+		}
+	}
+	for _, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+			buf.WriteRune(c)
+		case quoted:
+			buf.WriteRune(c)
+		case unicode.IsSpace(c):
+			flush()
+		case c == '(' || c == ')':
+			flush()
+			tokens = append(tokens, string(c))
+		case c == '!' && buf.Len() == 0:
+			tokens = append(tokens, string(c))
+		default:
+			buf.WriteRune(c)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("query: unterminated quote")
+	}
+	flush()
+	return tokens, nil
+}
+
+func and(lhs, rhs TypeFilterFunc) TypeFilterFunc {
+	return func(t TypeInfo) bool { return lhs(t) && rhs(t) }
+}
+
+func or(lhs, rhs TypeFilterFunc) TypeFilterFunc {
+	return func(t TypeInfo) bool { return lhs(t) || rhs(t) }
+}
+
+// KindOf returns the kind of a type as used in queries, e.g. "struct".
+func KindOf(t types.Type) string {
+	switch Unalias(t).(type) {
+	case *types.Array:
+		return "array"
+	case *types.Basic:
+		return "basic"
+	case *types.Chan:
+		return "chan"
+	case *types.Interface:
+		return "interface"
+	case *types.Map:
+		return "map"
+	case *types.Pointer:
+		return "pointer"
+	case *types.Signature:
+		return "func"
+	case *types.Slice:
+		return "slice"
+	case *types.Struct:
+		return "struct"
+	}
+	return "unknown"
+}
@@ -0,0 +1,97 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dtgorski/typex/internal/testdata/p2"
+	"github.com/dtgorski/typex/internal/testdata/p4"
+)
+
+func TestParseQuery_Error(t *testing.T) {
+	for _, q := range []string{
+		"", "kind", "kind:", "foo:bar", "(kind:struct", "kind:struct)",
+		"kind:struct AND", "NOT", "!", "kind:struct !", `name:"foo`, "kind:strcut",
+	} {
+		if _, err := ParseQuery(q); err == nil {
+			t.Errorf("unexpected, %q", q)
+		}
+	}
+}
+
+func TestParseQuery_Match(t *testing.T) {
+	pkgPath := reflect.TypeOf(p2.T{}).PkgPath()
+
+	for _, c := range []struct {
+		query string
+		roots string
+	}{
+		{"kind:struct", "S T"},
+		{"kind:struct AND NOT has-field:Fn", "T"},
+		{"kind:struct !has-field:Fn", "T"},
+		{"!kind:struct", "F I"},
+		{"!!kind:struct", "S T"},
+		{"kind:func OR kind:basic", "F I"},
+		{"NOT (kind:func OR kind:basic)", "S T"},
+		{"!(kind:func OR kind:basic)", "S T"},
+		{"has-field:BoolType", "T"},
+		{"tag:tags", "T"},
+		{"tag:json tag:tags", "T"},
+		{"tag:yaml", ""},
+		{`name:"^[FI]$"`, "F I"},
+		{"pkg:p2$ kind:basic", "I"},
+		{"implements:error", ""},
+	} {
+		if got := queryRoots(t, pkgPath, c.query); got != c.roots {
+			t.Errorf("%q: unexpected %q, want %q", c.query, got, c.roots)
+		}
+	}
+}
+
+func TestParseQuery_Select(t *testing.T) {
+	pkgPath := reflect.TypeOf(p4.A{}).PkgPath()
+
+	for _, c := range []struct {
+		query string
+		roots string
+	}{
+		{"implements:p4.S", "A"},
+		{"implements:p4.T", "A"},
+		{"implements:testdata/p4.T kind:interface", ""},
+		{"implements:p4.X", ""},
+		{"kind:struct !implements:p4.S", "B C D"},
+		{"doc:pointer", ""},
+		{`doc:"is implemented by"`, "S T"},
+		{`doc:"^[AC] is marked"`, "A C"},
+		{"doc:TypeScript !doc:skipped", "C"},
+	} {
+		if got := queryRoots(t, pkgPath, c.query); got != c.roots {
+			t.Errorf("%q: unexpected %q, want %q", c.query, got, c.roots)
+		}
+	}
+}
+
+func queryRoots(t *testing.T, pkgPath, q string) string {
+	query, err := ParseQuery(q)
+	if err != nil {
+		t.Errorf("unexpected %s", err)
+		return ""
+	}
+	pac := Packagist{TypeFilterFunc: query}
+	if _, err := pac.Inspect(pkgPath); err != nil {
+		t.Errorf("unexpected %s", err)
+		return ""
+	}
+	roots := make([]string, 0)
+	for name, depth := range pac.depth {
+		if depth == 0 {
+			roots = append(roots, strings.TrimPrefix(name, pkgPath+"."))
+		}
+	}
+	sort.Strings(roots)
+	return strings.Join(roots, " ")
+}
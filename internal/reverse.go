@@ -0,0 +1,122 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"go/types"
+	"sort"
+)
+
+type (
+	// ReverseIndex carries the edges between named types in
+	// the opposite direction: from a type to its referrers.
+	ReverseIndex struct {
+		types map[string]*types.Named
+		edges map[string][]Reference
+	}
+
+	// Referrer is a node in the chain of referencing types.
+	// A type referred to on several chains is expanded at its
+	// first occurrence only, later occurrences are Repeated and
+	// carry no Referrers.
+	Referrer struct {
+		Type      *types.Named
+		Field     string
+		Referrers []*Referrer
+		Repeated  bool
+	}
+)
+
+// Reverse builds a reverse reference index over the named
+// types declared in the packages named by the given patterns,
+// inspected in the first of the Contexts.
+func (p *Packagist) Reverse(patterns ...string) (*ReverseIndex, error) {
+	p.init()
+	x := &ReverseIndex{
+		types: make(map[string]*types.Named),
+		edges: make(map[string][]Reference),
+	}
+	if len(patterns) == 0 {
+		return x, nil
+	}
+	pkgs, err := p.load(p.contexts()[0], patterns...)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs {
+		scope := pkg.Types.Scope()
+
+		for _, name := range scope.Names() {
+			if !p.isExported(name) {
+				continue
+			}
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !p.PathFilterFunc(obj.Pkg().Path()+"."+name) {
+				continue
+			}
+			if t, ok := obj.Type().(*types.Named); ok {
+				x.types[t.String()] = t
+				x.add(References(t, p.IncludeUnexported))
+			}
+		}
+	}
+	return x, nil
+}
+
+func (x *ReverseIndex) add(refs []Reference) {
+	for _, ref := range refs {
+		s := ref.To.String()
+		x.types[s] = ref.To
+		x.edges[s] = append(x.edges[s], ref)
+	}
+}
+
+// Query returns the chains of referencing types up to the roots
+// for each indexed type matching the filter, ordered by name.
+// Each referrer is visited once per queried type, references
+// closing a cycle are left out.
+func (x *ReverseIndex) Query(filter PathFilterFunc) []*Referrer {
+	names := make([]string, 0)
+	for name := range x.types {
+		if filter(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	refs := make([]*Referrer, 0, len(names))
+	for _, name := range names {
+		t := x.types[name]
+		visits := map[*types.Named]visit{t: visiting}
+		refs = append(refs, x.referrers(&Referrer{Type: t}, visits))
+	}
+	return refs
+}
+
+type visit int
+
+const (
+	visiting visit = iota + 1
+	visited
+)
+
+func (x *ReverseIndex) referrers(r *Referrer, visits map[*types.Named]visit) *Referrer {
+	r.Referrers = make([]*Referrer, 0)
+
+	for _, e := range x.edges[r.Type.String()] {
+		rr := &Referrer{Type: e.From, Field: e.Field}
+
+		switch visits[e.From] {
+		case visiting:
+			continue
+		case visited:
+			rr.Repeated = true
+			r.Referrers = append(r.Referrers, rr)
+		default:
+			visits[e.From] = visiting
+			r.Referrers = append(r.Referrers, x.referrers(rr, visits))
+		}
+	}
+	visits[r.Type] = visited
+	return r
+}
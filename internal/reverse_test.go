@@ -0,0 +1,108 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"github.com/dtgorski/typex/internal/testdata/p2"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestPackagist_Reverse_1(t *testing.T) {
+	loader := func(c *packages.Config, p ...string) ([]*packages.Package, error) {
+		return nil, errors.New("error")
+	}
+	pac := Packagist{
+		PackageLoaderFunc: loader,
+	}
+	if _, err := pac.Reverse("."); err == nil {
+		t.Error("unexpected")
+	}
+}
+
+func TestPackagist_Reverse_2(t *testing.T) {
+	pkgPath := reflect.TypeOf(p2.T{}).PkgPath()
+
+	pac := Packagist{}
+	idx, err := pac.Reverse(pkgPath)
+	if err != nil {
+		t.Error("unexpected")
+		return
+	}
+
+	refs := idx.Query(CreatePathFilterFunc([]string{`p2\.I$`}, nil))
+	if len(refs) != 1 || refs[0].Type.String() != pkgPath+".I" {
+		t.Error("unexpected")
+		return
+	}
+
+	got := make(map[string]bool)
+	for _, r := range refs[0].Referrers {
+		got[r.Type.Obj().Name()+"."+r.Field] = true
+	}
+	if !got["F."] || !got["T.IntType"] || len(got) != 2 {
+		t.Errorf("unexpected %v", got)
+	}
+}
+
+func TestReverseIndex_Query_Diamond(t *testing.T) {
+	pkg := types.NewPackage("x", "x")
+	named := func(name string) *types.Named {
+		obj := types.NewTypeName(token.NoPos, pkg, name, nil)
+		return types.NewNamed(obj, types.NewStruct(nil, nil), nil)
+	}
+
+	// Stacked diamonds: each T<i> is referred to by L<i> and R<i>,
+	// both referred to by T<i+1>. The paths to the top grow with 2^n.
+	const n = 40
+	x := &ReverseIndex{
+		types: make(map[string]*types.Named),
+		edges: make(map[string][]Reference),
+	}
+	top := named("T0")
+	for i := 1; i <= n; i++ {
+		l, r, next := named(fmt.Sprint("L", i)), named(fmt.Sprint("R", i)), named(fmt.Sprint("T", i))
+		x.add([]Reference{
+			{From: l, Field: "T", To: top},
+			{From: r, Field: "T", To: top},
+			{From: next, Field: "L", To: l},
+			{From: next, Field: "R", To: r},
+		})
+		top = next
+	}
+
+	refs := x.Query(CreatePathFilterFunc([]string{`^x\.T0$`}, nil))
+	if len(refs) != 1 {
+		t.Fatalf("unexpected %d", len(refs))
+	}
+
+	expanded := make(map[string]int)
+	repeated := 0
+	var walk func([]*Referrer)
+	walk = func(refs []*Referrer) {
+		for _, r := range refs {
+			if r.Repeated {
+				repeated++
+				continue
+			}
+			expanded[r.Type.String()]++
+			walk(r.Referrers)
+		}
+	}
+	walk(refs[0].Referrers)
+
+	if len(expanded) != 3*n || repeated != n {
+		t.Errorf("unexpected %d/%d", len(expanded), repeated)
+	}
+	for name, count := range expanded {
+		if count != 1 {
+			t.Errorf("unexpected %s: %d", name, count)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package rust
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func TestNewModuleLayout(t *testing.T) {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{`p5\.`}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(p5.Order{}).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+
+	re := typex.CreatePathReplaceFunc([]string{".*/testdata:"})
+	tr := TypeRender{PathReplaceFunc: re}
+
+	buf := &bytes.Buffer{}
+	tw := typex.TreeWalk{Layout: NewModuleLayout(buf)}
+
+	if err := tw.Walk(tr.Render(types)); err != nil {
+		t.Error("unexpected")
+	}
+
+	want := `pub mod p5 {
+    #[derive(Debug, Clone, PartialEq, serde::Serialize, serde::Deserialize)]
+    pub struct Item {
+        pub sku: String,
+        pub quantity: i32,
+    }
+    #[derive(Debug, Clone, Copy, PartialEq, serde_repr::Serialize_repr, serde_repr::Deserialize_repr)]
+    #[repr(u8)]
+    pub enum Level {
+        Low = 0,
+        High = 1,
+    }
+    #[derive(Debug, Clone, PartialEq, serde::Serialize, serde::Deserialize)]
+    pub struct Order {
+        pub id: String,
+        pub status: Status,
+        #[serde(default, skip_serializing_if = "Option::is_none")]
+        pub level: Option<Level>,
+        pub items: Vec<Item>,
+        pub labels: std::collections::HashMap<String, String>,
+        pub created: chrono::DateTime<chrono::Utc>,
+        #[serde(default, skip_serializing_if = "Option::is_none")]
+        pub note: Option<String>,
+        #[serde(default, skip_serializing_if = "Option::is_none")]
+        pub r#ref: Option<Box<super::p2::S>>,
+    }
+    #[derive(Debug, Clone, Copy, PartialEq, serde_repr::Serialize_repr, serde_repr::Deserialize_repr)]
+    #[repr(i64)]
+    pub enum Status {
+        Open = 1,
+        Closed = 2,
+    }
+}
+`
+	got := buf.String()
+	if i := strings.Index(got, "pub mod p5 {"); i < 0 || got[i:] != want {
+		t.Errorf("unexpected\n%s", got)
+	}
+}
+
+func TestTypeRender_Keywords(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", `package p
+type T struct {
+	Type  int32
+	Crate int32
+	Self  int32
+	Super int32
+}`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check("p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := TypeRender{}
+	buf := &bytes.Buffer{}
+	tw := typex.TreeWalk{Layout: NewModuleLayout(buf)}
+
+	if err := tw.Walk(tr.Render(typex.TypeMap{"p.T": pkg.Scope().Lookup("T").Type()})); err != nil {
+		t.Error("unexpected")
+	}
+	for _, want := range []string{
+		"        #[serde(rename = \"Type\")]\n        pub r#type: i32,\n",
+		"        #[serde(rename = \"Crate\")]\n        pub crate_: i32,\n",
+		"        #[serde(rename = \"Self\")]\n        pub self_: i32,\n",
+		"        #[serde(rename = \"Super\")]\n        pub super_: i32,\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("unexpected\n%s", buf)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package rust
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"strconv"
+	"strings"
+	"unicode"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
+)
+
+type (
+	// TypeRender renders Go types as Rust structs, enums and type
+	// aliases deriving serde's Serialize and Deserialize.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+		TagKey            string
+		SortFields        bool
+	}
+
+	context struct {
+		writer *bytes.Buffer
+		module string
+	}
+)
+
+const derive = "#[derive(Debug, Clone, PartialEq, serde::Serialize, serde::Deserialize)]"
+
+// Render converts a TypeMap to a PathMap. Structs are rendered as
+// structs, anonymous structs as structs named after the enclosing
+// declaration and the field, enumerations as enums, other named
+// types as type aliases. The paths mirror the replaced package
+// paths, which are rendered as nested modules.
+func (r *TypeRender) Render(m typex.TypeMap) typex.PathMap {
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		SortFields:        r.SortFields,
+	}
+	pathMap := make(typex.PathMap)
+
+	for _, d := range b.Build(m).Decls {
+		module := strings.Join(split(d.Path), "/")
+		ctx := &context{writer: &bytes.Buffer{}, module: module}
+
+		switch d.Kind {
+		case model.Struct:
+			r.writeStruct(ctx, d)
+		case model.Enum:
+			r.writeEnum(ctx, d)
+		case model.Alias:
+			r.writeNested(ctx, d)
+			r.write(ctx, "\npub type %s = %s;", d.Name, r.typeOf(ctx, d.Type))
+		}
+		pathMap[module+"/"+d.Name] = strings.TrimPrefix(ctx.writer.String(), "\n")
+	}
+	return pathMap
+}
+
+// Path returns the PathMap key of a qualified type name.
+func (r *TypeRender) Path(s string) string {
+	p, n := r.names().Split(s)
+	return strings.Join(split(p), "/") + "/" + n
+}
+
+// writeNested writes the declarations of the anonymous structs of
+// a declaration, which precede the declaration.
+func (r *TypeRender) writeNested(ctx *context, d *model.Decl) {
+	for _, n := range d.Nested {
+		r.writeStruct(ctx, n)
+	}
+}
+
+func (r *TypeRender) writeStruct(ctx *context, d *model.Decl) {
+	r.writeNested(ctx, d)
+
+	fields := bytes.Buffer{}
+	r.writeFields(ctx, &fields, d)
+
+	r.write(ctx, "\n%s", derive)
+	if fields.Len() == 0 {
+		r.write(ctx, "\npub struct %s {}", d.Flat())
+		return
+	}
+	r.write(ctx, "\npub struct %s {\n%s}", d.Flat(), fields.String())
+}
+
+func (r *TypeRender) writeFields(ctx *context, w *bytes.Buffer, d *model.Decl) {
+	for _, fld := range d.Fields {
+		typ := r.typeOf(ctx, fld.Type)
+
+		attrs := make([]string, 0)
+		name := identifier(typex.SnakeCase(fld.Name))
+		if strings.TrimPrefix(name, "r#") != fld.Wire {
+			attrs = append(attrs, "rename = "+strconv.Quote(fld.Wire))
+		}
+		if fld.Optional || fld.Type.Nullable {
+			attrs = append(attrs, "default", `skip_serializing_if = "Option::is_none"`)
+			if !fld.Type.Nullable {
+				typ = "Option<" + typ + ">"
+			}
+		}
+		if len(attrs) > 0 {
+			_, _ = fmt.Fprintf(w, "    #[serde(%s)]\n", strings.Join(attrs, ", "))
+		}
+		_, _ = fmt.Fprintf(w, "    pub %s: %s,\n", name, typ)
+	}
+}
+
+// typeOf returns the Rust type of a type reference. Nullable types
+// are rendered as optional, references to structs are boxed to
+// allow recursion.
+func (r *TypeRender) typeOf(ctx *context, t *model.Type) string {
+	typ := "serde_json::Value"
+
+	switch t.Kind {
+	case model.Bool, model.Int, model.Uint, model.Float, model.String:
+		typ = basic(t)
+	case model.Bytes:
+		typ = "String" // encoding/json encodes []byte as base64 string
+	case model.Time:
+		typ = "chrono::DateTime<chrono::Utc>"
+	case model.List:
+		typ = "Vec<" + r.typeOf(ctx, t.Elem) + ">"
+	case model.Map:
+		typ = "std::collections::HashMap<" + r.keyOf(ctx, t.Key) + ", " + r.typeOf(ctx, t.Elem) + ">"
+	case model.Ref:
+		typ = r.reference(ctx, t.Decl)
+		if t.Nullable && t.Decl.Kind == model.Struct {
+			typ = "Box<" + typ + ">"
+		}
+	}
+	if t.Nullable {
+		return "Option<" + typ + ">"
+	}
+	return typ
+}
+
+// keyOf returns the type of a map key, JSON object keys are strings
+// or integers, floats are not hashable in Rust.
+func (r *TypeRender) keyOf(ctx *context, t *model.Type) string {
+	switch u := model.Unalias(t, true); {
+	case u.Nullable:
+	case u.Kind == model.Int, u.Kind == model.Uint, u.Kind == model.String:
+		return r.typeOf(ctx, t)
+	}
+	return "String"
+}
+
+func basic(t *model.Type) string {
+	switch t.Kind {
+	case model.Bool:
+		return "bool"
+	case model.String:
+		return "String"
+	case model.Int:
+		return "i" + strconv.Itoa(t.Bits)
+	case model.Uint:
+		return "u" + strconv.Itoa(t.Bits)
+	case model.Float:
+		return "f" + strconv.Itoa(t.Bits)
+	}
+	return "serde_json::Value"
+}
+
+// reference returns the path of a declaration relative to the module
+// of the current declaration.
+func (r *TypeRender) reference(ctx *context, d *model.Decl) string {
+	module, name := strings.Join(split(d.Path), "/"), d.Flat()
+	if module == ctx.module {
+		return name
+	}
+	from, to := split(ctx.module), split(module)
+
+	i := 0
+	for i < len(from) && i < len(to) && from[i] == to[i] {
+		i++
+	}
+	parts := make([]string, 0)
+	for range from[i:] {
+		parts = append(parts, "super")
+	}
+	parts = append(parts, to[i:]...)
+	return strings.Join(append(parts, name), "::")
+}
+
+func (r *TypeRender) writeEnum(ctx *context, d *model.Decl) {
+	if d.Type.Kind == model.String {
+		r.write(ctx, "%s\npub enum %s {\n", derive, d.Name)
+	} else {
+		r.write(ctx, "#[derive(Debug, Clone, Copy, PartialEq, serde_repr::Serialize_repr, serde_repr::Deserialize_repr)]\n")
+		r.write(ctx, "#[repr(%s)]\npub enum %s {\n", basic(d.Type), d.Name)
+	}
+	seen := make(map[string]bool)
+	for _, c := range d.Values {
+		n := strings.ToUpper(c.Name[:1]) + c.Name[1:]
+		v := c.Value.ExactString()
+		if seen[n] || seen[v] {
+			continue // Rust rejects duplicate variants and discriminants
+		}
+		seen[n], seen[v] = true, true
+
+		if c.Value.Kind() == constant.String {
+			r.write(ctx, "    #[serde(rename = %s)]\n    %s,\n", strconv.Quote(constant.StringVal(c.Value)), n)
+		} else {
+			r.write(ctx, "    %s = %s,\n", n, v)
+		}
+	}
+	r.write(ctx, "}")
+}
+
+func (TypeRender) write(ctx *context, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(ctx.writer, f, a...)
+}
+
+// split returns the module names of a path.
+func split(path string) []string {
+	parts := make([]string, 0)
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			parts = append(parts, identifier(strings.Map(func(c rune) rune {
+				if c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+					return c
+				}
+				return '_'
+			}, s)))
+		}
+	}
+	return parts
+}
+
+// identifier returns a raw identifier for Rust keywords.
+func identifier(s string) string {
+	switch s {
+	case "as", "async", "await", "break", "const", "continue", "dyn",
+		"else", "enum", "extern", "false", "fn", "for", "if", "impl", "in",
+		"let", "loop", "match", "mod", "move", "mut", "pub", "ref", "return",
+		"static", "struct", "trait", "true", "type", "unsafe", "use", "where",
+		"while", "abstract", "become", "box", "do", "final", "macro",
+		"override", "priv", "try", "typeof", "unsized", "virtual", "yield":
+		return "r#" + s
+	case "crate", "self", "super", "Self":
+		return s + "_" // not allowed as raw identifiers
+	}
+	return s
+}
+
+func (r *TypeRender) names() typex.Names {
+	return typex.Names{PathReplaceFunc: r.PathReplaceFunc, IncludeUnexported: r.IncludeUnexported}
+}
+
+// Reserved reports whether a type name or a package path segment
+// is a keyword or clashes with a name the rendered Rust code relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "Box", "Option", "Result", "Self", "String", "Vec", "crate",
+		"self", "serde", "serde_json", "std", "super":
+		return true
+	}
+	return identifier(s) != s
+}
@@ -0,0 +1,231 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/scanner"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type (
+	// source is a set of Go files of a directory,
+	// inspected without the go command.
+	source struct {
+		dir   string
+		files []string
+	}
+
+	importerFunc func(path string) (*types.Package, error)
+)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// sources separates the package patterns from the arguments naming
+// Go files or directories outside of any module. The files of the
+// latter are grouped by directory, directories are expanded to the
+// Go files matching the build context.
+func (p *Packagist) sources(ctx BuildContext, args []string) ([]string, []source, error) {
+	patterns := make([]string, 0, len(args))
+	srcs := make([]source, 0)
+	add := func(dir string, files ...string) {
+		for i := range srcs {
+			if srcs[i].dir == dir {
+				srcs[i].files = append(srcs[i].files, files...)
+				return
+			}
+		}
+		srcs = append(srcs, source{dir, files})
+	}
+
+	for _, arg := range args {
+		abs, err := filepath.Abs(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if strings.HasSuffix(arg, ".go") {
+			add(filepath.Dir(abs), abs)
+			continue
+		}
+		if fi, err := os.Stat(abs); err != nil || !fi.IsDir() || inModule(abs) {
+			patterns = append(patterns, arg)
+			continue
+		}
+		files, err := p.goFiles(ctx, abs)
+		if err != nil {
+			return nil, nil, err
+		}
+		add(abs, files...)
+	}
+	return patterns, srcs, nil
+}
+
+// goFiles returns the Go files of a directory matching the build
+// context, test files only if IncludeTestFiles is set.
+func (p *Packagist) goFiles(ctx BuildContext, dir string) ([]string, error) {
+	bc := build.Default
+	if ctx.GOOS != "" {
+		bc.GOOS = ctx.GOOS
+	}
+	if ctx.GOARCH != "" {
+		bc.GOARCH = ctx.GOARCH
+	}
+	bc.BuildTags = ctx.Tags
+	bc.OpenFile = func(path string) (io.ReadCloser, error) {
+		if src, ok := p.Overlay[path]; ok {
+			return io.NopCloser(bytes.NewReader(src)), nil
+		}
+		return os.Open(path)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if strings.HasSuffix(name, "_test.go") && !p.IncludeTestFiles {
+			continue
+		}
+		if ok, err := bc.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	return files, nil
+}
+
+// inModule reports whether a directory is part of a module or workspace.
+func inModule(dir string) bool {
+	for {
+		for _, name := range []string{"go.mod", "go.work"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// check parses and type-checks the files of a source. The package
+// path is the name of the package, the imported packages are loaded
+// by the go command in the directory of the files.
+func (p *Packagist) check(ctx BuildContext, src source) (*packages.Package, error) {
+	pkg := &packages.Package{Fset: p.fset}
+
+	for _, name := range src.files {
+		var text interface{}
+		if b, ok := p.Overlay[name]; ok {
+			text = b
+		}
+		file, err := parser.ParseFile(p.fset, name, text, parser.ParseComments)
+		var list scanner.ErrorList
+		if errors.As(err, &list) {
+			for _, e := range list {
+				pkg.Errors = append(pkg.Errors, packages.Error{Pos: e.Pos.String(), Msg: e.Msg, Kind: packages.ParseError})
+			}
+		} else if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			pkg.Syntax = append(pkg.Syntax, file)
+		}
+	}
+	if len(pkg.Syntax) == 0 {
+		return nil, errors.New("no Go files in " + src.dir)
+	}
+	pkg.Name = pkg.Syntax[0].Name.Name
+	pkg.ID, pkg.PkgPath = pkg.Name, pkg.Name
+
+	deps, err := p.loadImports(ctx, src.dir, pkg.Syntax)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range deps {
+		for _, e := range dep.Errors {
+			pkg.Errors = append(pkg.Errors, packages.Error{Pos: e.Pos, Msg: e.Msg, Kind: packages.ListError})
+		}
+	}
+	conf := types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if dep, ok := deps[path]; ok && dep.Types != nil {
+				return dep.Types, nil
+			}
+			if path == "unsafe" {
+				return types.Unsafe, nil
+			}
+			return nil, errors.New("could not import " + path)
+		}),
+		Error: func(err error) {
+			if e, ok := err.(types.Error); ok {
+				pos := e.Fset.Position(e.Pos).String()
+				pkg.Errors = append(pkg.Errors, packages.Error{Pos: pos, Msg: e.Msg, Kind: packages.TypeError})
+			}
+		},
+	}
+	pkg.Types, _ = conf.Check(pkg.PkgPath, p.fset, pkg.Syntax, nil)
+	return pkg, nil
+}
+
+// loadImports loads the packages imported by the files, indexed by path.
+func (p *Packagist) loadImports(ctx BuildContext, dir string, files []*ast.File) (map[string]*packages.Package, error) {
+	paths := make([]string, 0)
+	seen := map[string]bool{"unsafe": true, "C": true}
+	for _, file := range files {
+		for _, spec := range file.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err == nil && !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	deps := make(map[string]*packages.Package, len(paths))
+	if len(paths) == 0 {
+		return deps, nil
+	}
+	sort.Strings(paths)
+
+	mode := packages.NeedName
+	mode |= packages.NeedTypes
+	mode |= packages.NeedImports
+	mode |= packages.NeedDeps
+	conf := &packages.Config{
+		Mode:       mode,
+		Dir:        dir,
+		Fset:       p.fset,
+		Env:        ctx.environ(p.Env),
+		BuildFlags: ctx.buildFlags(),
+		Overlay:    p.Overlay,
+	}
+	pkgs, err := p.PackageLoaderFunc(conf, paths...)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range pkgs {
+		deps[pkg.PkgPath] = pkg
+	}
+	return deps, nil
+}
@@ -0,0 +1,49 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPackagist_sources(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"a.go":       "package a\n",
+		"a_linux.go": "package a\n",
+		"a_test.go":  "package a\n",
+		"b.go":       "//go:build extra\n\npackage a\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inModule(dir) {
+		t.Skip("temporary directory inside of a module")
+	}
+	p := Packagist{}
+	patterns, srcs, err := p.sources(BuildContext{GOOS: "windows"}, []string{".", "./...", dir, "x/y.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(patterns, []string{".", "./..."}) {
+		t.Errorf("unexpected %v", patterns)
+	}
+	abs, _ := filepath.Abs("x")
+	want := []source{
+		{dir, []string{filepath.Join(dir, "a.go")}},
+		{abs, []string{filepath.Join(abs, "y.go")}},
+	}
+	if !reflect.DeepEqual(srcs, want) {
+		t.Errorf("unexpected %v", srcs)
+	}
+
+	p.IncludeTestFiles = true
+	_, srcs, _ = p.sources(BuildContext{GOOS: "linux", Tags: []string{"extra"}}, []string{dir})
+	if len(srcs) != 1 || len(srcs[0].files) != 4 {
+		t.Errorf("unexpected %v", srcs)
+	}
+}
@@ -0,0 +1,67 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package swift
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	typex "github.com/dtgorski/typex/internal"
+)
+
+type (
+	namespaceLayout struct {
+		writer io.Writer
+		indent int
+		fresh  bool
+	}
+)
+
+// NewNamespaceLayout implements the Layout and Framer interfaces.
+// Paths are rendered as nested caseless enums serving as namespaces.
+func NewNamespaceLayout(w io.Writer) typex.Layout {
+	return &namespaceLayout{writer: w}
+}
+
+func (n *namespaceLayout) Open() {
+	n.write(n.writer, "import Foundation\n")
+	n.fresh = false
+}
+
+func (n *namespaceLayout) Close() {}
+
+func (n *namespaceLayout) Enter(path string, _ bool) {
+	i := strings.LastIndex(path, "/")
+	if !n.fresh {
+		n.write(n.writer, "\n")
+	}
+	p := strings.Repeat(" ", n.indent<<2)
+	n.write(n.writer, "%senum %s {\n", p, path[i+1:])
+	n.indent++
+	n.fresh = true
+}
+
+func (n *namespaceLayout) Print(line string, first, _ bool) {
+	if first && !n.fresh {
+		n.write(n.writer, "\n")
+	}
+	n.fresh = false
+
+	p := strings.Repeat(" ", n.indent<<2)
+	if line == "" {
+		p = ""
+	}
+	n.write(n.writer, "%s%s\n", p, line)
+}
+
+func (n *namespaceLayout) Leave(_ string, _ bool) {
+	n.indent--
+	p := strings.Repeat(" ", n.indent<<2)
+	n.write(n.writer, "%s}\n", p)
+	n.fresh = false
+}
+
+func (namespaceLayout) write(w io.Writer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
@@ -0,0 +1,101 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package swift
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p2"
+	"github.com/dtgorski/typex/internal/testdata/p5"
+)
+
+func inspect(t *testing.T, filter string, v interface{}) typex.TypeMap {
+	pac := typex.Packagist{
+		PathFilterFunc: typex.CreatePathFilterFunc([]string{filter}, nil),
+	}
+	types, err := pac.Inspect(reflect.TypeOf(v).PkgPath())
+	if err != nil {
+		t.Error("unexpected")
+	}
+	return types
+}
+
+func TestNewNamespaceLayout(t *testing.T) {
+	re := typex.CreatePathReplaceFunc([]string{".*/testdata:"})
+	tr := TypeRender{PathReplaceFunc: re}
+
+	buf := &bytes.Buffer{}
+	tw := typex.TreeWalk{Layout: NewNamespaceLayout(buf)}
+
+	if err := tw.Walk(tr.Render(inspect(t, `p5\.`, p5.Order{}))); err != nil {
+		t.Error("unexpected")
+	}
+
+	want := `enum p5 {
+    struct Item: Codable {
+        let sku: String
+        let quantity: Int32
+
+        enum CodingKeys: String, CodingKey {
+            case sku
+            case quantity
+        }
+    }
+
+    enum Level: UInt8, Codable {
+        case low = 0
+        case high = 1
+    }
+
+    struct Order: Codable {
+        let id: String
+        let status: Status
+        let level: Level?
+        let items: [Item]
+        let labels: [String: String]
+        let created: Date
+        let note: String?
+        let ref: p2.S?
+
+        enum CodingKeys: String, CodingKey {
+            case id
+            case status
+            case level
+            case items
+            case labels
+            case created
+            case note
+            case ref
+        }
+    }
+
+    enum Status: Int64, Codable {
+        case ` + "`open`" + ` = 1
+        case closed = 2
+    }
+}
+`
+	got := buf.String()
+	if !strings.HasPrefix(got, "import Foundation\n") {
+		t.Errorf("unexpected\n%s", got)
+	}
+	if i := strings.Index(got, "enum p5 {"); i < 0 || got[i:] != want {
+		t.Errorf("unexpected\n%s", got)
+	}
+}
+
+func TestTypeRender_Recursive(t *testing.T) {
+	tr := TypeRender{PathReplaceFunc: typex.CreatePathReplaceFunc([]string{".*/testdata:"})}
+	decl := tr.Render(inspect(t, `p2\.T`, p2.T{}))["p2/T"]
+
+	if !strings.HasPrefix(decl, "final class T: Codable {") {
+		t.Errorf("unexpected\n%s", decl)
+	}
+	if !strings.Contains(decl, "    // chanType: unsupported type <-chan *bool\n") {
+		t.Errorf("unexpected\n%s", decl)
+	}
+}
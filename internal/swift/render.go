@@ -0,0 +1,354 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package swift
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"strconv"
+	"strings"
+	"unicode"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
+)
+
+type (
+	// TypeRender renders Go types as Swift Codable structs and enums.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+		TagKey            string
+		SortFields        bool
+	}
+
+	context struct {
+		namespace string
+	}
+)
+
+// Render converts a TypeMap to a PathMap. Structs are rendered as
+// structs, anonymous structs as structs nested in the enclosing one.
+// Structs containing themselves through pointers are rendered as
+// final classes, since Swift value types cannot be recursive.
+// Enumerations are rendered as enums, other named types as type
+// aliases. The paths mirror the replaced package paths, which are
+// rendered as nested namespaces.
+func (r *TypeRender) Render(m typex.TypeMap) typex.PathMap {
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		SortFields:        r.SortFields,
+	}
+	pathMap := make(typex.PathMap)
+
+	for _, d := range b.Build(m).Decls {
+		ctx := &context{namespace: namespace(d.Path)}
+
+		var decl string
+		switch d.Kind {
+		case model.Struct:
+			decl = r.structure(ctx, d, isRecursive(d))
+		case model.Enum:
+			decl = r.enum(d)
+		case model.Alias:
+			if !isSupported(d.Type) {
+				continue
+			}
+			nested := make([]string, 0, len(d.Nested)+1)
+			for _, n := range d.Nested {
+				nested = append(nested, r.structure(ctx, n, false))
+			}
+			decl = strings.Join(append(nested, "typealias "+d.Name+" = "+r.typeOf(ctx, d.Type)), "\n\n")
+		}
+		pathMap[strings.ReplaceAll(ctx.namespace, ".", "/")+"/"+d.Name] = decl
+	}
+	return pathMap
+}
+
+// Path returns the PathMap key of a qualified type name.
+func (r *TypeRender) Path(s string) string {
+	p, n := r.names().Split(s)
+	return strings.ReplaceAll(namespace(p), ".", "/") + "/" + n
+}
+
+// structure returns the declaration of a struct and the declarations
+// of its anonymous structs, nested in the struct.
+func (r *TypeRender) structure(ctx *context, d *model.Decl, class bool) string {
+	props, keys := bytes.Buffer{}, bytes.Buffer{}
+	r.writeFields(ctx, &props, &keys, d)
+
+	buf := bytes.Buffer{}
+	if class {
+		r.write(&buf, "final class %s: Codable {", declName(d))
+	} else {
+		r.write(&buf, "struct %s: Codable {", declName(d))
+	}
+	if props.Len() == 0 && len(d.Nested) == 0 {
+		r.write(&buf, "}")
+		return buf.String()
+	}
+	r.write(&buf, "\n%s", props.String())
+
+	if keys.Len() > 0 {
+		r.write(&buf, "\n    enum CodingKeys: String, CodingKey {\n%s    }\n", keys.String())
+	}
+	for _, n := range d.Nested {
+		r.write(&buf, "\n%s", indent(r.structure(ctx, n, false)))
+	}
+	r.write(&buf, "}")
+	return buf.String()
+}
+
+func (r *TypeRender) writeFields(ctx *context, props, keys *bytes.Buffer, d *model.Decl) {
+	for _, fld := range d.Fields {
+		name := identifier(typex.LowerCamelCase(fld.Name))
+
+		if !isSupported(fld.Type) {
+			r.write(props, "    // %s: unsupported type %s\n", name, typex.Abbreviate(fld.Origin.Type()))
+			continue
+		}
+		typ := r.typeOf(ctx, fld.Type)
+		if fld.Optional {
+			typ = strings.TrimSuffix(typ, "?") + "?"
+		}
+		r.write(props, "    let %s: %s\n", name, typ)
+
+		if strings.Trim(name, "`") != fld.Wire {
+			r.write(keys, "        case %s = %s\n", name, quote(fld.Wire))
+		} else {
+			r.write(keys, "        case %s\n", name)
+		}
+	}
+}
+
+// typeOf returns the Swift type of a type reference supported by
+// Codable.
+func (r *TypeRender) typeOf(ctx *context, t *model.Type) string {
+	typ := ""
+
+	switch t.Kind {
+	case model.Bool, model.Int, model.Uint, model.Float, model.String:
+		typ = basic(t)
+	case model.Bytes:
+		typ = "Data" // encoding/json encodes []byte as base64 string
+	case model.Time:
+		typ = "Date" // requires the .iso8601 date coding strategy
+	case model.List:
+		typ = "[" + r.typeOf(ctx, t.Elem) + "]"
+	case model.Map:
+		typ = "[" + keyOf(t.Key) + ": " + r.typeOf(ctx, t.Elem) + "]"
+	case model.Ref:
+		typ = reference(ctx, t.Decl)
+	}
+	if t.Nullable {
+		return typ + "?"
+	}
+	return typ
+}
+
+// isSupported reports whether a type has a Codable counterpart.
+func isSupported(t *model.Type) bool {
+	switch t.Kind {
+	case model.Any:
+		return false
+	case model.List, model.Map:
+		return isSupported(t.Elem)
+	case model.Ref:
+		return t.Decl.Kind != model.Alias || isSupported(t.Decl.Type)
+	}
+	return true
+}
+
+// isRecursive reports whether a struct contains itself through
+// fields not backed by an array or a dictionary.
+func isRecursive(target *model.Decl) bool {
+	visited := make(map[*model.Decl]bool)
+
+	var walk func(d *model.Decl) bool
+	walk = func(d *model.Decl) bool {
+		if visited[d] {
+			return false
+		}
+		visited[d] = true
+
+		switch d.Kind {
+		case model.Struct:
+			for _, f := range d.Fields {
+				if f.Type.Kind == model.Ref && (f.Type.Decl == target || walk(f.Type.Decl)) {
+					return true
+				}
+			}
+		case model.Alias:
+			if d.Type.Kind == model.Ref && (d.Type.Decl == target || walk(d.Type.Decl)) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(target)
+}
+
+// keyOf returns the type of a dictionary key, Codable encodes
+// dictionaries with String and Int keys as JSON objects only.
+func keyOf(t *model.Type) string {
+	if u := model.Unalias(t, true); !u.Nullable && (u.Kind == model.Int || u.Kind == model.Uint) {
+		return "Int"
+	}
+	return "String"
+}
+
+func basic(t *model.Type) string {
+	switch t.Kind {
+	case model.Bool:
+		return "Bool"
+	case model.String:
+		return "String"
+	case model.Int:
+		return "Int" + strconv.Itoa(t.Bits)
+	case model.Uint:
+		return "UInt" + strconv.Itoa(t.Bits)
+	case model.Float:
+		if t.Bits == 32 {
+			return "Float"
+		}
+		return "Double"
+	}
+	return ""
+}
+
+// reference returns the name of a declaration, qualified by its
+// namespace when declared in another namespace.
+func reference(ctx *context, d *model.Decl) string {
+	ns, name := namespace(d.Path), d.Qualified(".")
+	if ns == ctx.namespace || ns == "" {
+		return name
+	}
+	return ns + "." + name
+}
+
+// declName returns the name of a declaration. Structs of anonymous
+// structs in type aliases are declared on the top level.
+func declName(d *model.Decl) string {
+	if d.Parent != nil && d.Parent.Kind == model.Struct {
+		return d.Name
+	}
+	return d.Qualified("")
+}
+
+func (r *TypeRender) enum(d *model.Decl) string {
+	buf := bytes.Buffer{}
+
+	if d.Type.Kind == model.String {
+		r.write(&buf, "enum %s: String, Codable {\n", d.Name)
+	} else {
+		r.write(&buf, "enum %s: %s, Codable {\n", d.Name, basic(d.Type))
+	}
+	seen := make(map[string]bool)
+	for _, c := range d.Values {
+		n := identifier(typex.LowerCamelCase(c.Name))
+		v := c.Value.ExactString()
+		if c.Value.Kind() == constant.String {
+			v = quote(constant.StringVal(c.Value))
+		}
+		if seen[n] || seen[v] {
+			continue // Swift rejects duplicate cases and raw values
+		}
+		seen[n], seen[v] = true, true
+		r.write(&buf, "    case %s = %s\n", n, v)
+	}
+	r.write(&buf, "}")
+	return buf.String()
+}
+
+func (TypeRender) write(w *bytes.Buffer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
+
+// namespace returns the namespace of a path consisting of valid
+// identifiers.
+func namespace(path string) string {
+	parts := make([]string, 0)
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			parts = append(parts, identifier(strings.Map(func(c rune) rune {
+				if c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+					return c
+				}
+				return '_'
+			}, s)))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// identifier returns a valid Swift identifier, keywords are
+// escaped with backticks.
+func identifier(s string) string {
+	switch s {
+	case "associatedtype", "class", "deinit", "enum", "extension",
+		"fileprivate", "func", "import", "init", "inout", "internal", "let",
+		"open", "operator", "private", "precedencegroup", "protocol", "public",
+		"rethrows", "static", "struct", "subscript", "typealias", "var",
+		"break", "case", "catch", "continue", "default", "defer", "do", "else",
+		"fallthrough", "for", "guard", "if", "in", "repeat", "return", "throw",
+		"switch", "where", "while", "Any", "as", "false", "is", "nil", "self",
+		"Self", "super", "throws", "true", "try":
+		return "`" + s + "`"
+	}
+	if s != "" && !unicode.IsLetter([]rune(s)[0]) && s[0] != '_' {
+		return "_" + s
+	}
+	return s
+}
+
+// quote returns a Swift string literal.
+func quote(s string) string {
+	b := strings.Builder{}
+	b.WriteByte('"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case !unicode.IsPrint(c):
+			_, _ = fmt.Fprintf(&b, `\u{%x}`, c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func indent(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = "    " + l
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (r *TypeRender) names() typex.Names {
+	return typex.Names{PathReplaceFunc: r.PathReplaceFunc, IncludeUnexported: r.IncludeUnexported}
+}
+
+// Reserved reports whether a type name or a package path segment
+// is a keyword or clashes with a name the rendered Swift code relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "Bool", "Codable", "CodingKey", "CodingKeys", "Data", "Date",
+		"Double", "Float", "Foundation", "Int", "Int8", "Int16", "Int32",
+		"Int64", "String", "UInt", "UInt8", "UInt16", "UInt32", "UInt64":
+		return true
+	}
+	return identifier(s) != s
+}
@@ -0,0 +1,182 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 06/2020
+// Partly based on reflect/type.go · Copyright 2009 The Go Authors
+// Partly based on encoding/json/tags.go · Copyright 2011 The Go Authors
+
+package internal
+
+import (
+	"strconv"
+	"strings"
+)
+
+type (
+	// A StructTag is the tag string in a struct field.
+	//
+	// By convention, tag strings are a concatenation of optionally
+	// space-separated key:"value" pairs. Each key is a non-empty
+	// string consisting of non-control characters other than space
+	// (U+0020 ' '), quote (U+0022 '"'), and colon (U+003A ':').
+	// Each value is quoted using U+0022 '"' characters and Go string
+	// literal syntax.
+	StructTag string
+
+	// TagOptions is the string following a comma in a struct field's
+	// tag, or the empty string. It does not include the leading comma.
+	TagOptions string
+)
+
+// Get returns the values associated with key and options in the tag string.
+func (t StructTag) Get(key string) (string, TagOptions) {
+	value := t.lookup(key)
+	if i := strings.Index(value, ","); i != -1 {
+		return value[:i], TagOptions(value[i+1:])
+	}
+	return value, ""
+}
+
+// lookup returns the unquoted value associated with key in the tag string.
+func (t StructTag) lookup(key string) string {
+	tag := t
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon. A space, a quote or a control character is a syntax error.
+		// Strictly speaking, control chars include the range [0x7f, 0x9f], not just
+		// [0x00, 0x1f], but in practice, we ignore the multi-byte control characters
+		// as it is simpler to inspect the tag's bytes than the tag's runes.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := string(tag[:i])
+		tag = tag[i+1:]
+
+		// Scan quoted string to find value.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value := string(tag[:i+1])
+		tag = tag[i+1:]
+
+		if key == name {
+			value, err := strconv.Unquote(value)
+			if err != nil {
+				break
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+// Contains reports whether a comma-separated list of options
+// contains a particular substr flag. substr must be surrounded
+// by a string boundary or commas.
+func (o TagOptions) Contains(name string) bool {
+	if len(o) == 0 {
+		return false
+	}
+	s := string(o)
+	for s != "" {
+		var next string
+		i := strings.Index(s, ",")
+		if i >= 0 {
+			s, next = s[:i], s[i+1:]
+		}
+		if s == name {
+			return true
+		}
+		s = next
+	}
+	return false
+}
+
+type (
+	// FieldTag describes how a serialization library treats a struct
+	// field according to the tag value found under a given tag key.
+	FieldTag struct {
+		Name     string // Name on the wire, never empty.
+		Skip     bool   // Field is ignored by the library.
+		Optional bool   // Field is omitted when its value is empty.
+		Inline   bool   // Fields of the embedded struct are flattened.
+		Quoted   bool   // Value is encoded as a JSON string.
+	}
+)
+
+// DefaultTagKey is the struct tag key used for field renaming,
+// omission and optionality unless configured otherwise.
+const DefaultTagKey = "json"
+
+// Field interprets the tag value associated with key using the option
+// semantics of the library commonly bound to that key (json, yaml, xml,
+// bson, msgpack, toml, mapstructure). Unknown keys follow the semantics
+// of encoding/json. The name argument is the Go name of the field.
+func (t StructTag) Field(key, name string) FieldTag {
+	value, opts := t.Get(key)
+	f := FieldTag{Name: value}
+
+	switch key {
+	case "yaml":
+		f.Optional = opts.Contains("omitempty")
+		f.Inline = opts.Contains("inline")
+		if f.Name == "" {
+			f.Name = strings.ToLower(name)
+		}
+	case "xml":
+		// Attributes ("attr") are members like child elements, the
+		// models do not tell them apart. Fields holding the content
+		// of the element ("chardata", "cdata", "innerxml") or the
+		// unmatched elements ("any") have no name on the wire and
+		// keep their Go name.
+		f.Optional = opts.Contains("omitempty")
+		f.Skip = opts.Contains("comment") || name == "XMLName"
+		if i := strings.LastIndex(f.Name, " "); i > -1 {
+			f.Name = f.Name[i+1:] // namespace prefix
+		}
+		if i := strings.LastIndex(f.Name, ">"); i > -1 {
+			f.Name = f.Name[i+1:] // parent element chain
+		}
+	case "bson":
+		f.Optional = opts.Contains("omitempty")
+		f.Inline = opts.Contains("inline")
+		if f.Name == "" {
+			f.Name = strings.ToLower(name)
+		}
+	case "msgpack":
+		f.Optional = opts.Contains("omitempty")
+		f.Inline = opts.Contains("inline")
+	case "toml":
+		f.Optional = opts.Contains("omitempty") || opts.Contains("omitzero")
+	case "mapstructure":
+		f.Optional = opts.Contains("omitempty")
+		f.Inline = opts.Contains("squash")
+	default:
+		f.Optional = opts.Contains("omitempty") || opts.Contains("omitzero")
+		f.Quoted = opts.Contains("string")
+	}
+
+	if value == "-" && t.lookup(key) == "-" {
+		f.Skip = true // "-," names the field "-"
+	}
+	if f.Name == "" {
+		f.Name = name
+	}
+	return f
+}
@@ -0,0 +1,43 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package internal
+
+import "testing"
+
+func TestStructTag_Field(t *testing.T) {
+	for i, c := range []struct {
+		tag  StructTag
+		key  string
+		name string
+		want FieldTag
+	}{
+		{``, "json", "Foo", FieldTag{Name: "Foo"}},
+		{`json:"foo"`, "json", "Foo", FieldTag{Name: "foo"}},
+		{`json:"-"`, "json", "Foo", FieldTag{Name: "-", Skip: true}},
+		{`json:"-,"`, "json", "Foo", FieldTag{Name: "-"}},
+		{`json:",omitempty"`, "json", "Foo", FieldTag{Name: "Foo", Optional: true}},
+		{`json:"foo,string"`, "json", "Foo", FieldTag{Name: "foo", Quoted: true}},
+		{`json:"foo" yaml:"bar"`, "yaml", "Foo", FieldTag{Name: "bar"}},
+		{`json:"foo"`, "yaml", "FooBar", FieldTag{Name: "foobar"}},
+		{`yaml:",inline"`, "yaml", "Foo", FieldTag{Name: "foo", Inline: true}},
+		{`xml:"ns foo,attr,omitempty"`, "xml", "Foo", FieldTag{Name: "foo", Optional: true}},
+		{`xml:"a>b>foo"`, "xml", "Foo", FieldTag{Name: "foo"}},
+		{`xml:",attr"`, "xml", "Foo", FieldTag{Name: "Foo"}},
+		{`xml:",chardata"`, "xml", "Text", FieldTag{Name: "Text"}},
+		{`xml:",cdata"`, "xml", "Text", FieldTag{Name: "Text"}},
+		{`xml:",innerxml"`, "xml", "Raw", FieldTag{Name: "Raw"}},
+		{`xml:",any"`, "xml", "Rest", FieldTag{Name: "Rest"}},
+		{`xml:",comment"`, "xml", "Foo", FieldTag{Name: "Foo", Skip: true}},
+		{``, "xml", "XMLName", FieldTag{Name: "XMLName", Skip: true}},
+		{``, "bson", "FooBar", FieldTag{Name: "foobar"}},
+		{`bson:",inline"`, "bson", "Foo", FieldTag{Name: "foo", Inline: true}},
+		{`msgpack:",omitempty"`, "msgpack", "Foo", FieldTag{Name: "Foo", Optional: true}},
+		{`toml:"foo,omitzero"`, "toml", "Foo", FieldTag{Name: "foo", Optional: true}},
+		{`mapstructure:",squash"`, "mapstructure", "Foo", FieldTag{Name: "Foo", Inline: true}},
+		{`custom:"foo,omitempty"`, "custom", "Foo", FieldTag{Name: "foo", Optional: true}},
+	} {
+		if got := c.tag.Field(c.key, c.name); got != c.want {
+			t.Errorf("%d: unexpected %+v, want %+v", i, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package p4
+
+type (
+	// A is marked for export.
+	//typex:export
+	A struct {
+		B B
+	}
+
+	// B is not marked for export, but skipped in TypeScript layouts.
+	//typex:skip ts
+	B struct {
+		V int
+	}
+
+	// C is marked for export, renamed in TypeScript layouts.
+	//typex:export ts name=DTO
+	//typex:export ts-class name=VO
+	C struct {
+		A A
+	}
+
+	// D is not marked.
+	D struct{}
+
+	// S is implemented by A.
+	S interface {
+		String() string
+	}
+
+	// T is implemented by *A.
+	T interface {
+		Set(string) error
+	}
+)
+
+// String has a value receiver.
+func (A) String() string { return "" }
+
+// Set has a pointer receiver.
+func (*A) Set(string) error { return nil }
+
+func (*A) unexported() {}
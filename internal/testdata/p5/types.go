@@ -0,0 +1,44 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package p5
+
+import (
+	"time"
+
+	"github.com/dtgorski/typex/internal/testdata/p2"
+)
+
+type (
+	Status int
+	Level  uint8
+
+	// Order is a customer order.
+	Order struct {
+		ID      string            `json:"id"` // ID is the order number.
+		Status  Status            `json:"status"`
+		Level   *Level            `json:"level,omitempty"`
+		Items   []Item            `json:"items"`
+		Labels  map[string]string `json:"labels"`
+		Created time.Time         `json:"created"`
+		Note    *string           `json:"note,omitempty"`
+		Ref     *p2.S             `json:"ref"`
+		Hidden  string            `json:"-"`
+	}
+
+	Item struct {
+		SKU      string `json:"sku"`
+		Quantity int32  `json:"quantity"`
+	}
+)
+
+const (
+	// StatusOpen awaits fulfillment.
+	StatusOpen Status = iota + 1
+	StatusClosed
+)
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+	LevelMax = LevelHigh
+)
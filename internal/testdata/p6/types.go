@@ -0,0 +1,78 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package p6
+
+import "time"
+
+type (
+	// Color is a string enumeration.
+	Color string
+
+	// Stamp is an alias of time.Time.
+	Stamp = time.Time
+
+	// Alias is an alias of a struct declared in this package.
+	Alias = Node
+
+	// Node is a recursive type.
+	Node struct {
+		Value    int     `json:"value"`
+		Parent   *Node   `json:"parent,omitempty"`
+		Children []*Node `json:"children"`
+	}
+
+	// Pair is a generic type.
+	Pair[K comparable, V any] struct {
+		Key   K `json:"key"`
+		Value V `json:"value"`
+	}
+
+	// Number is a constraint interface.
+	Number interface {
+		~int | ~float64
+	}
+
+	// Base is embedded into Tagged.
+	Base struct {
+		ID string `json:"id"`
+	}
+
+	// Meta is embedded into Tagged under the name of its tag.
+	Meta struct {
+		Version int `json:"version" yaml:"version"`
+	}
+
+	// Extra is embedded into Tagged, inlined by the yaml tag only.
+	Extra struct {
+		Label string `json:"label" yaml:"label"`
+	}
+
+	// Tagged covers the struct tag options.
+	Tagged struct {
+		Base
+		Meta    `json:"meta" yaml:"meta"`
+		Extra   `json:"extra" yaml:",inline"`
+		Name    string               `json:"name"`
+		Count   int64                `json:"count,string"`
+		Note    *string              `json:"note,omitempty"`
+		Secret  string               `json:"-"`
+		Dash    string               `json:"-,"`
+		Color   Color                `json:"color"`
+		Created Stamp                `json:"created"`
+		Tree    Alias                `json:"tree"`
+		Pair    Pair[string, int]    `json:"pair"`
+		Pairs   []Pair[Color, *Node] `json:"pairs"`
+		plain   bool
+	}
+
+	// hidden is neither exported nor referred to.
+	hidden struct {
+		Z int
+	}
+)
+
+const (
+	Red   Color = "red"
+	Green Color = "green"
+	Blue  Color = "blue"
+)
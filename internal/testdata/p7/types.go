@@ -0,0 +1,10 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+// Package p7 does not compile, it tests the partial inspection.
+package p7
+
+type (
+	Broken struct {
+		Missing Undefined
+	}
+)
@@ -0,0 +1,9 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+//go:build extra
+
+package p8
+
+type Extra struct {
+	Config Config
+}
@@ -0,0 +1,5 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package p8
+
+type Handle int32
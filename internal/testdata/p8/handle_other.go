@@ -0,0 +1,7 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+//go:build !linux
+
+package p8
+
+type Handle uintptr
@@ -0,0 +1,11 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+// Package p8 declares types depending on the build context.
+package p8
+
+type (
+	Config struct {
+		Name   string
+		Handle Handle
+	}
+)
@@ -4,16 +4,21 @@ package internal
 
 import (
 	"io/ioutil"
+	"sort"
 	"strings"
 
 	"golang.org/x/tools/godoc/vfs/mapfs"
 )
 
 type (
-	// TreeWalk knows about Layout.
+	// TreeWalk knows about Layout. The entries of a directory are
+	// walked in the order of the Ranking of the PathMap keys, if any,
+	// directories rank like their first entry. Otherwise, and for
+	// entries without rank, the order is alphabetical.
 	TreeWalk struct {
 		Layout
-		indent int
+		Ranking Ranking
+		indent  int
 	}
 
 	// The Layout interface shapes a type visitor.
@@ -23,6 +28,13 @@ type (
 		Leave(path string, last bool)
 	}
 
+	// The Framer interface is implemented by layouts emitting
+	// a preamble and a trailer around the walked PathMap.
+	Framer interface {
+		Open()
+		Close()
+	}
+
 	// PathMap carries the mapping between the fully
 	// qualified import paths and their type projections.
 	PathMap map[string]string
@@ -30,10 +42,15 @@ type (
 
 // Walk traverses a PathMap according to the paths.
 func (t *TreeWalk) Walk(m PathMap) error {
+	if f, ok := t.Layout.(Framer); ok {
+		f.Open()
+		defer f.Close()
+	}
 	if len(m) == 0 {
 		return nil
 	}
 	mapFs := mapfs.New(m)
+	ranks := t.ranks(m)
 	t.indent = -1
 
 	var walk func(dir string) error
@@ -45,6 +62,11 @@ func (t *TreeWalk) Walk(m PathMap) error {
 		if err != nil {
 			return err
 		}
+		if ranks != nil {
+			sort.SliceStable(finfo, func(i, j int) bool {
+				return ranks.Of(dir+finfo[i].Name()) < ranks.Of(dir+finfo[j].Name())
+			})
+		}
 		for i, n := 0, len(finfo); i < n; i++ {
 			path := dir + finfo[i].Name()
 
@@ -75,3 +97,22 @@ func (t *TreeWalk) Walk(m PathMap) error {
 	}
 	return walk("/")
 }
+
+// ranks returns the Ranking of the files and directories of
+// the PathMap, keyed by their absolute paths.
+func (t *TreeWalk) ranks(m PathMap) Ranking {
+	if t.Ranking == nil {
+		return nil
+	}
+	ranks := make(Ranking)
+	for p := range m {
+		i, ok := t.Ranking[p]
+		if !ok {
+			continue
+		}
+		for p = "/" + p; p != ""; p = p[:strings.LastIndex(p, "/")] {
+			ranks.Put(p, i)
+		}
+	}
+	return ranks
+}
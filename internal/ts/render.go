@@ -6,208 +6,298 @@ package ts
 import (
 	"bytes"
 	"fmt"
-	"go/token"
 	"go/types"
 	"io"
+	"strconv"
 	"strings"
+	"unicode"
 
 	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/model"
 )
 
 type (
 	// TypeRender renders TypeScript types or classes.
 	TypeRender struct {
 		PathReplaceFunc   typex.PathReplaceFunc
+		PositionFunc      typex.PositionFunc
+		DocFunc           typex.DocFunc
 		IncludeUnexported bool
+		TagKey            string
+		SortFields        bool
 
 		indent int
 	}
 
 	context struct {
 		writer   io.Writer
-		seenType []types.Type
 		needCtor bool
 		expoObjs bool
 	}
 )
 
-// Render converts a TypeMap to a PathMap.
+// Render converts a TypeMap to a PathMap. Anonymous structs are
+// rendered inline, well-known types by their JSON representation.
 func (r *TypeRender) Render(m typex.TypeMap, exportObj bool) typex.PathMap {
 	r.indent = 0
 
-	var exClass bool
+	b := model.Builder{
+		PathReplaceFunc:   r.PathReplaceFunc,
+		DocFunc:           r.DocFunc,
+		IncludeUnexported: r.IncludeUnexported,
+		TagKey:            r.TagKey,
+		SortFields:        r.SortFields,
+	}
 	pathMap := make(typex.PathMap)
 
-	for p, t := range m {
-		if !r.isTranslatable(t.Underlying()) {
-			continue
-		}
-		path, name := r.pathAndName(p)
-		buf, typ := bytes.Buffer{}, t.Underlying()
+	for _, d := range b.Build(m).Decls {
+		buf := bytes.Buffer{}
+		exClass := exportObj && d.Kind == model.Struct
 
-		if exClass = exportObj; exClass {
-			_, exClass = typ.(*types.Struct)
-		}
 		ctx := context{
 			writer:   &buf,
-			seenType: make([]types.Type, 0),
 			needCtor: exClass,
 			expoObjs: exClass,
 		}
-		r.writeType(ctx, typ)
+		if d.Kind == model.Struct {
+			r.writeStruct(ctx, d)
+		} else {
+			r.writeType(ctx, d.Type)
+		}
 
+		lines := r.docLines(d.Doc)
+		if nt, ok := d.Origin.(*types.Named); ok && r.PositionFunc != nil {
+			if pos := r.PositionFunc(nt); pos != "" {
+				lines = append(lines, "@see "+pos)
+			}
+		}
+		doc := bytes.Buffer{}
+		r.writeComment(context{writer: &doc}, lines)
+
+		path := d.Name
+		if d.Path != "" {
+			path = d.Path + "/" + d.Name
+		}
 		if exClass {
-			pathMap[path] = "export class " + name + " " + buf.String()
+			pathMap[path] = doc.String() + "export class " + d.Name + " " + buf.String()
 		} else {
-			pathMap[path] = "export type " + name + " = " + buf.String()
+			pathMap[path] = doc.String() + "export type " + d.Name + " = " + buf.String()
 		}
 	}
 	return pathMap
 }
 
-func (r *TypeRender) writeType(ctx context, t types.Type) {
-	for _, tt := range ctx.seenType {
-		if tt == t {
-			return
-		}
+// Path returns the PathMap key of a qualified type name.
+func (r *TypeRender) Path(s string) string {
+	p, n := r.names().Split(s)
+	if p = strings.Trim(p, "/"); p == "" {
+		return n
 	}
-	ctx.seenType = append(ctx.seenType, t)
-
-	switch tt := t.(type) {
-	case *types.Basic:
-		r.writeBasic(ctx, tt)
-
-	case *types.Chan:
-		r.write(ctx, "any")
-
-	case *types.Array:
-		r.writeType(ctx, tt.Elem())
-		r.write(ctx, "[/* %d */]", tt.Len())
+	return p + "/" + n
+}
 
-	case *types.Interface:
-		r.write(ctx, "any")
+func (r *TypeRender) writeType(ctx context, t *model.Type) {
+	switch t.Kind {
+	case model.Bool:
+		r.write(ctx, "boolean")
 
-	case *types.Map:
-		r.writeMap(ctx, tt)
+	case model.Int, model.Uint, model.Float:
+		r.write(ctx, "number")
 
-	case *types.Named:
-		r.writeNamed(ctx, tt)
+	case model.String, model.Bytes, model.Time:
+		r.write(ctx, "string")
 
-	case *types.Pointer:
-		r.writeType(ctx, tt.Elem())
+	case model.List:
+		r.writeType(ctx, t.Elem)
+		if at, ok := t.Origin.(*types.Array); ok {
+			r.write(ctx, "[/* %d */]", at.Len())
+		} else {
+			r.write(ctx, "[]")
+		}
 
-	case *types.Signature:
-		r.write(ctx, "any")
+	case model.Map:
+		r.writeMap(ctx, t)
 
-	case *types.Slice:
-		r.writeType(ctx, tt.Elem())
-		r.write(ctx, "[]")
+	case model.Ref:
+		r.writeRef(ctx, t.Decl)
 
-	case *types.Struct:
-		r.writeStruct(ctx, tt)
+	default:
+		r.writeAny(ctx, t)
 	}
 }
 
-func (r *TypeRender) writeBasic(ctx context, t *types.Basic) {
-	switch k := t.Info(); true {
-	case (k & types.IsBoolean) != 0:
-		r.write(ctx, "boolean")
-	case (k & types.IsNumeric) != 0:
-		r.write(ctx, "number")
-	case (k & types.IsString) != 0:
-		r.write(ctx, "string")
-	default:
-		r.write(ctx, "any")
+// writeAny writes the type of values without serializable
+// counterpart. Structs left out of the inspection, e.g. due to a
+// depth limit, are unknown.
+func (r *TypeRender) writeAny(ctx context, t *model.Type) {
+	if nt, ok := t.Origin.(*types.Named); ok {
+		if _, ok := nt.Underlying().(*types.Struct); ok {
+			r.write(ctx, "unknown")
+			return
+		}
 	}
+	r.write(ctx, "any")
 }
 
-func (r *TypeRender) writeNamed(ctx context, t *types.Named) {
-	p := "any"
-	if r.isTranslatable(t.Underlying()) {
-		if obj := t.Obj(); obj != nil {
-			p = ""
-			if obj.Pkg() != nil {
-				p += obj.Pkg().Path() + "."
-			}
-			p += obj.Name()
-			p = r.replacePath(p)
-			p = strings.ReplaceAll(p, "/", ".")
-		}
+func (r *TypeRender) writeRef(ctx context, d *model.Decl) {
+	if d.Parent != nil {
+		r.writeStruct(ctx, d)
+		return
+	}
+	if d.Path != "" {
+		r.write(ctx, "%s.", strings.ReplaceAll(d.Path, "/", "."))
 	}
-	r.write(ctx, p)
+	r.write(ctx, "%s", d.Name)
 }
 
-func (r *TypeRender) writeMap(ctx context, t *types.Map) {
+func (r *TypeRender) writeMap(ctx context, t *model.Type) {
 	r.write(ctx, "Record<")
-	if r.isValidMapKey(t.Key()) {
-		r.writeType(ctx, t.Key())
-	} else {
+	switch k := model.Unalias(t.Key, true); {
+	case k.Nullable:
+		r.write(ctx, "symbol")
+	case k.Kind == model.Int, k.Kind == model.Uint, k.Kind == model.Float, k.Kind == model.String:
+		r.writeType(ctx, t.Key)
+	case k.Kind == model.Time:
+		r.write(ctx, "string")
+	default:
 		r.write(ctx, "symbol")
 	}
 	r.write(ctx, ", ")
-	r.writeType(ctx, t.Elem())
+	r.writeType(ctx, t.Elem)
 	r.write(ctx, ">")
 }
 
-func (r *TypeRender) writeStruct(ctx context, t *types.Struct) {
-	void := true
+func (r *TypeRender) writeStruct(ctx context, d *model.Decl) {
 	r.indent++
 	r.write(ctx, "{")
 
 	ctor := ctx.needCtor && ctx.expoObjs
 	if ctor {
 		ctx.needCtor = false
+		r.writeMembers(ctx, d)
 		r.write(ctx, "\n")
 		r.writePadding(ctx)
 		r.write(ctx, "constructor(")
 		r.indent++
 	}
 
-	for i, n := 0, t.NumFields(); i < n; i++ {
-		tag, opt := (StructTag)(t.Tag(i)).Get("json")
-		if tag == "-" {
-			continue
-		}
-		fld := t.Field(i)
-		typ := fld.Type()
-		name := fld.Name()
+	void := r.writeFields(ctx, d, ctor)
+	if r.indent--; !void {
+		r.write(ctx, "\n")
+		r.writePadding(ctx)
+	}
+	if ctor {
+		r.writeAssignments(ctx, d)
+		r.indent--
+		r.writePadding(ctx)
+	}
+	r.write(ctx, "}")
+}
 
-		if !r.isExported(name) {
-			continue
-		}
-		if tt, ok := t.Field(i).Type().(*types.Named); ok {
-			if !r.isExported(tt.String()) {
-				continue
-			}
+// writeFields writes the fields of a struct, or the parameters of
+// a class constructor. Fields named other than by an identifier are
+// declared as members of the class instead of parameter properties.
+func (r *TypeRender) writeFields(ctx context, d *model.Decl, params bool) bool {
+	required := -1
+	for i, fld := range d.Fields {
+		if !fld.Optional {
+			required = i
 		}
+	}
+	for i, fld := range d.Fields {
 		r.write(ctx, "\n")
 		r.writePadding(ctx)
-		if ctx.expoObjs {
-			r.write(ctx, "readonly ")
+		if lines := r.docLines(fld.Doc); len(lines) > 0 {
+			r.writeComment(ctx, lines)
+			r.writePadding(ctx)
 		}
-		if tag != "" {
-			r.write(ctx, tag)
-		} else {
-			r.write(ctx, name)
+		name := property(fld.Wire)
+		switch {
+		case params && name != fld.Wire:
+			name = parameter(fld)
+		case ctx.expoObjs:
+			r.write(ctx, "readonly ")
 		}
-		if opt.Contains("omitempty") {
+		// optional parameters must not precede required ones
+		undef := params && fld.Optional && i < required
+		r.write(ctx, "%s", name)
+		if fld.Optional && !undef {
 			r.write(ctx, "?")
 		}
 		r.write(ctx, ": ")
-		r.writeType(ctx, typ)
+		r.writeType(ctx, fld.Type)
+		if undef {
+			r.write(ctx, " | undefined")
+		}
 		r.write(ctx, ",")
-		void = false
 	}
-	if r.indent--; !void {
-		r.write(ctx, "\n")
-		r.writePadding(ctx)
+	return len(d.Fields) == 0
+}
+
+// writeMembers writes the class members of fields which cannot be
+// declared as parameter properties.
+func (r *TypeRender) writeMembers(ctx context, d *model.Decl) {
+	for _, fld := range d.Fields {
+		if name := property(fld.Wire); name != fld.Wire {
+			r.write(ctx, "\n")
+			r.writePadding(ctx)
+			r.write(ctx, "readonly %s", name)
+			if fld.Optional {
+				r.write(ctx, "?")
+			}
+			r.write(ctx, ": ")
+			r.writeType(ctx, fld.Type)
+		}
 	}
-	if ctor {
-		r.indent--
+}
+
+func (r *TypeRender) writeAssignments(ctx context, d *model.Decl) {
+	void := true
+	for _, fld := range d.Fields {
+		if name := property(fld.Wire); name != fld.Wire {
+			if void {
+				r.write(ctx, ") {")
+				void = false
+			}
+			r.indent++
+			r.write(ctx, "\n")
+			r.writePadding(ctx)
+			r.write(ctx, "this[%s] = %s", name, parameter(fld))
+			r.indent--
+		}
+	}
+	if void {
 		r.write(ctx, ") {}\n")
+		return
+	}
+	r.write(ctx, "\n")
+	r.writePadding(ctx)
+	r.write(ctx, "}\n")
+}
+
+// writeComment writes a JSDoc comment of the given lines followed
+// by a line break, the comment is left out when there are no lines.
+func (r *TypeRender) writeComment(ctx context, lines []string) {
+	switch len(lines) {
+	case 0:
+	case 1:
+		r.write(ctx, "/** %s */\n", lines[0])
+	default:
+		r.write(ctx, "/**\n")
+		for _, l := range lines {
+			r.writePadding(ctx)
+			r.write(ctx, "%s\n", strings.TrimRight(" * "+l, " "))
+		}
 		r.writePadding(ctx)
+		r.write(ctx, " */\n")
 	}
-	r.write(ctx, "}")
+}
+
+func (r *TypeRender) docLines(doc string) []string {
+	if doc = strings.TrimSpace(doc); doc == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(doc, "*/", "*\\/"), "\n")
 }
 
 func (r *TypeRender) writePadding(ctx context) {
@@ -218,52 +308,43 @@ func (TypeRender) write(ctx context, f string, a ...interface{}) {
 	_, _ = fmt.Fprintf(ctx.writer, f, a...)
 }
 
-func (r *TypeRender) pathAndName(s string) (p, n string) {
-	p = r.replacePath(s)
-	n = p
-	i := strings.LastIndex(p, ".")
-	if i > -1 && i < len(p)-1 {
-		n = p[i+1:]
-		p = p[:i] + "/" + n
-	}
-	return p, n
-}
-
-func (r *TypeRender) replacePath(s string) string {
-	if r.PathReplaceFunc != nil {
-		return r.PathReplaceFunc(s)
+// property returns a property name, quoted unless it is an
+// identifier other than a reserved word.
+func property(s string) string {
+	if s == "" || Reserved(s) || strings.IndexFunc(s, func(c rune) bool {
+		return c != '_' && c != '$' && !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	}) > -1 || unicode.IsDigit([]rune(s)[0]) {
+		return strconv.Quote(s)
 	}
 	return s
 }
 
-func (r *TypeRender) isExported(s string) bool {
-	if r.IncludeUnexported {
-		return true
-	}
-	n := strings.ReplaceAll(s, ".", "/")
-	i := strings.LastIndex(n, "/")
-	return token.IsExported(n[i+1:])
+// parameter returns the constructor parameter name of a field
+// declared as class member.
+func parameter(f *model.Field) string {
+	return f.Name + "_"
 }
 
-func (r *TypeRender) isValidMapKey(t types.Type) bool {
-	switch tt := t.(type) {
-	case *types.Basic:
-		k := tt.Info()
-		return (k&types.IsNumeric) != 0 || (k&types.IsString) != 0
-	case *types.Map:
-		return r.isValidMapKey(tt.Key())
-	case *types.Named:
-		return r.isValidMapKey(tt.Underlying())
+// Reserved reports whether a name is a reserved word or clashes
+// with a type name the rendered TypeScript code relies on.
+func Reserved(s string) bool {
+	switch s {
+	case "break", "case", "catch", "class", "const", "continue", "debugger",
+		"default", "delete", "do", "else", "enum", "export", "extends",
+		"false", "finally", "for", "function", "if", "import", "in",
+		"instanceof", "new", "null", "return", "super", "switch", "this",
+		"throw", "true", "try", "typeof", "var", "void", "while", "with",
+		"implements", "interface", "let", "package", "private", "protected",
+		"public", "static", "yield", "await",
+		"any", "bigint", "boolean", "never", "number", "object", "string",
+		"symbol", "undefined", "unknown",
+		"Array", "Boolean", "Date", "Number", "Object", "Record", "String",
+		"Symbol":
+		return true
 	}
 	return false
 }
 
-func (r *TypeRender) isTranslatable(t types.Type) bool {
-	switch t.(type) {
-	case *types.Chan, *types.Interface, *types.Signature:
-		return false
-	case *types.Named:
-		return r.isTranslatable(t.Underlying())
-	}
-	return true
+func (r *TypeRender) names() typex.Names {
+	return typex.Names{PathReplaceFunc: r.PathReplaceFunc, IncludeUnexported: r.IncludeUnexported}
 }
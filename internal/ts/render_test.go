@@ -0,0 +1,21 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package ts
+
+import "testing"
+
+func TestProperty(t *testing.T) {
+	for s, want := range map[string]string{
+		"id":         "id",
+		"$ref":       "$ref",
+		"type":       "type",
+		"delete":     `"delete"`,
+		"first-name": `"first-name"`,
+		"1st":        `"1st"`,
+		"":           `""`,
+	} {
+		if got := property(s); got != want {
+			t.Errorf("unexpected %q: %s", s, got)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package uml
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	typex "github.com/dtgorski/typex/internal"
+)
+
+type (
+	mermaidLayout struct {
+		diagramLayout
+	}
+
+	plantUMLLayout struct {
+		diagramLayout
+	}
+
+	diagramLayout struct {
+		writer    io.Writer
+		relations []string
+		indent    int
+	}
+)
+
+// NewMermaidLayout implements the Layout interface. Mermaid class
+// diagrams do not nest namespaces, the classes are labeled with
+// their package qualified names instead.
+func NewMermaidLayout(w io.Writer, relations []string) typex.Layout {
+	return &mermaidLayout{diagramLayout{writer: w, relations: relations, indent: 1}}
+}
+
+func (l *mermaidLayout) Open() {
+	l.write("classDiagram\n")
+}
+
+func (l *mermaidLayout) Enter(string, bool) {}
+
+func (l *mermaidLayout) Leave(string, bool) {}
+
+func (l *mermaidLayout) Close() {
+	l.flush()
+}
+
+// NewPlantUMLLayout implements the Layout interface. Packages are
+// rendered as nested package blocks containing their classes.
+func NewPlantUMLLayout(w io.Writer, relations []string) typex.Layout {
+	return &plantUMLLayout{diagramLayout{writer: w, relations: relations}}
+}
+
+func (l *plantUMLLayout) Open() {
+	l.write("@startuml\n")
+	l.write("set separator ::\n")
+	l.write("hide empty members\n")
+}
+
+func (l *plantUMLLayout) Enter(path string, _ bool) {
+	i := strings.LastIndex(path, "/")
+	l.write("%spackage %s {\n", strings.Repeat(" ", l.indent<<2), path[i+1:])
+	l.indent++
+}
+
+func (l *plantUMLLayout) Leave(string, bool) {
+	l.indent--
+	l.write("%s}\n", strings.Repeat(" ", l.indent<<2))
+}
+
+func (l *plantUMLLayout) Close() {
+	l.flush()
+	l.write("@enduml\n")
+}
+
+// Print emits class declarations in place. The relations are written
+// on Close, both dialects expect them outside of class bodies and
+// package blocks.
+func (l *diagramLayout) Print(line string, _, _ bool) {
+	l.write("%s%s\n", strings.Repeat(" ", l.indent<<2), line)
+}
+
+func (l *diagramLayout) flush() {
+	for _, rel := range l.relations {
+		l.write("%s%s\n", strings.Repeat(" ", l.indent<<2), rel)
+	}
+}
+
+func (l *diagramLayout) write(f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(l.writer, f, a...)
+}
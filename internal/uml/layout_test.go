@@ -0,0 +1,93 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package uml
+
+import (
+	"bytes"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/testdata/p1"
+)
+
+// typex -l="mermaid" -r=".*/testdata:" ./internal/testdata/p1 > ./internal/testdata/fixture-mermaid.txt
+func TestNewMermaidLayout(t *testing.T) {
+	testLayout(t, "../testdata/fixture-mermaid.txt", Mermaid, NewMermaidLayout)
+}
+
+// typex -l="plantuml" -r=".*/testdata:" ./internal/testdata/p1 > ./internal/testdata/fixture-plantuml.txt
+func TestNewPlantUMLLayout(t *testing.T) {
+	testLayout(t, "../testdata/fixture-plantuml.txt", PlantUML, NewPlantUMLLayout)
+}
+
+func testLayout(t *testing.T, fixture string, d Dialect, l func(io.Writer, []string) typex.Layout) {
+	pac := typex.Packagist{}
+	path := reflect.TypeOf(p1.D{}).PkgPath()
+
+	types, err := pac.Inspect(path)
+	if err != nil {
+		t.Error("unexpected")
+	}
+
+	re := typex.CreatePathReplaceFunc([]string{".*/testdata:"})
+	tr := TypeRender{PathReplaceFunc: re, Dialect: d}
+
+	buf := &bytes.Buffer{}
+	tw := typex.TreeWalk{Layout: l(buf, tr.Relations(types, nil))}
+
+	if err := tw.Walk(tr.Render(types)); err != nil {
+		t.Error("unexpected")
+	}
+
+	fix, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Error("unexpected")
+	}
+	if !bytes.Equal(fix, buf.Bytes()) {
+		tmp, err := ioutil.TempFile("", "")
+		if err != nil {
+			t.Error(err)
+		}
+		defer func() { _ = os.Remove(tmp.Name()) }()
+
+		err = ioutil.WriteFile(tmp.Name(), buf.Bytes(), 0644)
+		if err != nil {
+			t.Error(err)
+		}
+		cmd := exec.Command("diff", "-u", fixture, tmp.Name())
+		out, _ := cmd.Output()
+		t.Error(string(out))
+	}
+}
+
+func TestTypeRender_MermaidIDs(t *testing.T) {
+	named := func(path, name string, u types.Type) *types.Named {
+		obj := types.NewTypeName(token.NoPos, types.NewPackage(path, path), name, nil)
+		return types.NewNamed(obj, u, nil)
+	}
+	x := named("a/b", "C", types.NewStruct(nil, nil))
+	y := named("a", "b_C", types.NewStruct(nil, nil))
+	z := named("a", "b_C_2", types.NewStruct([]*types.Var{
+		types.NewField(token.NoPos, nil, "X", x, false),
+		types.NewField(token.NoPos, nil, "Y", y, false),
+	}, nil))
+
+	m := typex.TypeMap{x.String(): x, y.String(): y, z.String(): z}
+	tr := TypeRender{Dialect: Mermaid}
+	tr.Render(m)
+
+	want := []string{
+		`a_b_C_2 --> "1" a_b_C_3 : X`,
+		`a_b_C_2 --> "1" a_b_C : Y`,
+	}
+	if got := tr.Relations(m, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected %q", got)
+	}
+}
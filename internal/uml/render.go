@@ -0,0 +1,315 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package uml
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	typex "github.com/dtgorski/typex/internal"
+)
+
+type (
+	// TypeRender renders Go types as UML class diagram declarations
+	// and relations in the Mermaid or PlantUML dialect.
+	TypeRender struct {
+		PathReplaceFunc   typex.PathReplaceFunc
+		IncludeUnexported bool
+		Dialect           Dialect
+		SortFields        bool
+		ids               map[string]string
+	}
+
+	// Dialect selects the class diagram syntax.
+	Dialect int
+)
+
+const (
+	// Mermaid denotes the Mermaid "classDiagram" syntax.
+	Mermaid Dialect = iota
+	// PlantUML denotes the PlantUML class diagram syntax.
+	PlantUML
+)
+
+// Render converts a TypeMap to a PathMap. Each type is rendered as
+// a class, structs with their fields and interfaces with their
+// methods. The relations between the classes are rendered by
+// Relations.
+func (r *TypeRender) Render(m typex.TypeMap) typex.PathMap {
+	r.index(m)
+	pathMap := make(typex.PathMap)
+
+	for p, t := range m {
+		path, name := r.names().PathAndName(p)
+		buf := bytes.Buffer{}
+
+		r.writeClass(&buf, p, name, t.Underlying())
+		pathMap[path] = buf.String()
+	}
+	return pathMap
+}
+
+// Relations returns the relations from the types of the TypeMap to
+// the types they refer to, ordered by the Ranking of the referring
+// types, if any, otherwise by their paths. Embedding is drawn as
+// inheritance, field references are drawn as associations with a
+// multiplicity. Relations to types not contained in the TypeMap are
+// omitted.
+func (r *TypeRender) Relations(m typex.TypeMap, ranking typex.Ranking) []string {
+	r.index(m)
+	keys := make([]string, 0, len(m))
+	for p := range m {
+		keys = append(keys, p)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return r.Path(keys[i]) < r.Path(keys[j])
+	})
+	ranking.Sort(keys)
+
+	rels := make([]string, 0)
+	for _, p := range keys {
+		nt, ok := m[p].(*types.Named)
+		if !ok {
+			continue
+		}
+		for _, ref := range typex.References(nt, r.IncludeUnexported) {
+			if _, ok := m[ref.To.String()]; !ok {
+				continue
+			}
+			buf := bytes.Buffer{}
+			r.writeRelation(&buf, p, ref)
+			rels = append(rels, buf.String())
+		}
+	}
+	return rels
+}
+
+// Path returns the PathMap key of a qualified type name.
+func (r *TypeRender) Path(s string) string {
+	p, _ := r.names().PathAndName(s)
+	return p
+}
+
+func (r *TypeRender) writeClass(w *bytes.Buffer, p, name string, t types.Type) {
+	members := make([]string, 0)
+
+	switch tt := typex.Unalias(t).(type) {
+	case *types.Struct:
+		for _, i := range typex.FieldOrder(tt, r.SortFields) {
+			f := tt.Field(i)
+			if f.Embedded() || !r.names().IsExported(f.Name()) {
+				continue
+			}
+			members = append(members, r.field(f.Name(), f.Type()))
+		}
+	case *types.Interface:
+		for i, n := 0, tt.NumExplicitMethods(); i < n; i++ {
+			f := tt.ExplicitMethod(i)
+			if !r.names().IsExported(f.Name()) {
+				continue
+			}
+			members = append(members, r.method(f.Name(), f.Type().(*types.Signature)))
+		}
+	}
+
+	kind := typex.KindOf(t)
+	if r.Dialect == PlantUML {
+		decl := "class"
+		switch kind {
+		case "interface":
+			decl = "interface"
+		case "struct":
+		default:
+			name += " <<" + kind + ">>"
+		}
+		r.write(w, "%s %s", decl, name)
+	} else {
+		r.write(w, "class %s[%s]", r.id(p), quote(r.label(p)))
+		if kind != "struct" {
+			members = append([]string{"<<" + kind + ">>"}, members...)
+		}
+	}
+
+	if len(members) == 0 {
+		return
+	}
+	r.write(w, " {")
+	for _, s := range members {
+		r.write(w, "\n    %s", s)
+	}
+	r.write(w, "\n}")
+}
+
+func (r *TypeRender) writeRelation(w *bytes.Buffer, p string, ref typex.Reference) {
+	from, to := r.id(p), r.id(ref.To.String())
+
+	switch ref.Kind {
+	case typex.RefEmbedded:
+		r.write(w, "%s <|-- %s", to, from)
+	case typex.RefSignature:
+		r.write(w, "%s ..> %s", from, to)
+	default:
+		r.write(w, "%s --> %s %s", from, quote(ref.Multiplicity), to)
+	}
+	if ref.Field != "" {
+		r.write(w, " : %s", ref.Field)
+	}
+}
+
+func (r *TypeRender) field(name string, t types.Type) string {
+	if r.Dialect == PlantUML {
+		return r.visibility(name) + name + " : " + r.typeString(t)
+	}
+	return r.visibility(name) + name + " " + r.typeString(t)
+}
+
+func (r *TypeRender) method(name string, sig *types.Signature) string {
+	s := r.visibility(name) + name + "(" + r.tupleString(sig.Params(), sig.Variadic()) + ")"
+
+	res := sig.Results()
+	switch {
+	case res.Len() == 0:
+		return s
+	case r.Dialect == PlantUML:
+		return s + " : " + r.resultString(res)
+	}
+	// Mermaid takes everything after the closing
+	// parenthesis as return type, nested parentheses
+	// would be consumed by the parameter list.
+	return s + " " + r.tupleString(res, false)
+}
+
+func (r *TypeRender) resultString(t *types.Tuple) string {
+	if t.Len() == 1 {
+		return r.typeString(t.At(0).Type())
+	}
+	return "(" + r.tupleString(t, false) + ")"
+}
+
+func (r *TypeRender) tupleString(t *types.Tuple, variadic bool) string {
+	s := make([]string, 0, t.Len())
+	for i, n := 0, t.Len(); i < n; i++ {
+		if typ := t.At(i).Type(); variadic && i == n-1 {
+			s = append(s, "..."+r.typeString(typ.(*types.Slice).Elem()))
+		} else {
+			s = append(s, r.typeString(typ))
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+// typeString returns a compact type expression. Anonymous structs
+// and interfaces are abbreviated, braces would terminate the class
+// body in both dialects.
+func (r *TypeRender) typeString(t types.Type) string {
+	switch tt := typex.Unalias(t).(type) {
+	case *types.Array:
+		return fmt.Sprintf("[%d]%s", tt.Len(), r.typeString(tt.Elem()))
+	case *types.Basic:
+		return tt.Name()
+	case *types.Chan:
+		switch tt.Dir() {
+		case types.SendOnly:
+			return "chan<- " + r.typeString(tt.Elem())
+		case types.RecvOnly:
+			return "<-chan " + r.typeString(tt.Elem())
+		}
+		return "chan " + r.typeString(tt.Elem())
+	case *types.Interface:
+		if tt.Empty() {
+			return "any"
+		}
+		return "interface"
+	case *types.Map:
+		return "map[" + r.typeString(tt.Key()) + "]" + r.typeString(tt.Elem())
+	case *types.Named:
+		return r.label(tt.String())
+	case *types.Pointer:
+		return "*" + r.typeString(tt.Elem())
+	case *types.Signature:
+		s := "func(" + r.tupleString(tt.Params(), tt.Variadic()) + ")"
+		if tt.Results().Len() > 0 {
+			s += " " + r.resultString(tt.Results())
+		}
+		return s
+	case *types.Slice:
+		return "[]" + r.typeString(tt.Elem())
+	case *types.Struct:
+		return "struct"
+	}
+	return t.String()
+}
+
+func (r *TypeRender) visibility(name string) string {
+	if token.IsExported(name) {
+		return "+"
+	}
+	return "-"
+}
+
+// id returns the identifier of a class. PlantUML addresses classes
+// by their package qualified name, Mermaid identifiers are limited
+// to alphanumeric characters and underscores, see index.
+func (r *TypeRender) id(s string) string {
+	if r.Dialect == PlantUML {
+		p, _ := r.names().PathAndName(s)
+		return strings.TrimPrefix(strings.ReplaceAll(p, "/", "::"), "::")
+	}
+	if id, ok := r.ids[s]; ok {
+		return id
+	}
+	return r.mermaidID(s)
+}
+
+// index assigns the Mermaid identifiers of the types in the TypeMap.
+// Names differing only in characters replaced by underscores get a
+// numeric suffix, in the order of the names.
+func (r *TypeRender) index(m typex.TypeMap) {
+	keys := make([]string, 0, len(m))
+	for p := range m {
+		keys = append(keys, p)
+	}
+	sort.Strings(keys)
+
+	r.ids = make(map[string]string, len(keys))
+	used := make(map[string]bool, len(keys))
+
+	for _, p := range keys {
+		base := r.mermaidID(p)
+		id := base
+		for i := 2; used[id]; i++ {
+			id = fmt.Sprintf("%s_%d", base, i)
+		}
+		r.ids[p], used[id] = id, true
+	}
+}
+
+func (r *TypeRender) mermaidID(s string) string {
+	p := r.names().Replace(s)
+	return strings.Map(func(c rune) rune {
+		if c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' {
+			return c
+		}
+		return '_'
+	}, strings.TrimPrefix(p, "/"))
+}
+
+func (r *TypeRender) label(s string) string {
+	return strings.TrimPrefix(strings.ReplaceAll(r.names().Replace(s), "/", "."), ".")
+}
+
+func (TypeRender) write(w *bytes.Buffer, f string, a ...interface{}) {
+	_, _ = fmt.Fprintf(w, f, a...)
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `'`) + `"`
+}
+
+func (r *TypeRender) names() typex.Names {
+	return typex.Names{PathReplaceFunc: r.PathReplaceFunc, IncludeUnexported: r.IncludeUnexported}
+}
@@ -3,16 +3,31 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"go/types"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 
 	typex "github.com/dtgorski/typex/internal"
+	"github.com/dtgorski/typex/internal/csharp"
+	"github.com/dtgorski/typex/internal/dart"
+	"github.com/dtgorski/typex/internal/dot"
+	"github.com/dtgorski/typex/internal/dump"
 	"github.com/dtgorski/typex/internal/go"
+	"github.com/dtgorski/typex/internal/graphql"
+	"github.com/dtgorski/typex/internal/java"
+	"github.com/dtgorski/typex/internal/kotlin"
+	"github.com/dtgorski/typex/internal/proto"
+	"github.com/dtgorski/typex/internal/python"
+	"github.com/dtgorski/typex/internal/rust"
+	"github.com/dtgorski/typex/internal/swift"
 	"github.com/dtgorski/typex/internal/ts"
+	"github.com/dtgorski/typex/internal/uml"
 )
 
 type (
@@ -20,101 +35,684 @@ type (
 		includeParts flagArray
 		excludeParts flagArray
 		replaceParts flagArray
+		reverseParts flagArray
+		inputParts   flagArray
+		stopPatterns flagArray
+		buildTagSets flagArray
+		environVars  flagArray
 		pathPatterns []string
+		typeQuery    typex.TypeFilterFunc
+		pathReplace  typex.PathReplaceFunc
+		typePosition typex.PositionFunc
+		typeDoc      typex.DocFunc
+		contexts     []typex.BuildContext
+		ranking      typex.Ranking
 		outputLayout *string
+		serialTagKey *string
+		outputFolder *string
+		lockFilePath *string
+		customScalar *string
+		onCollision  *string
+		renameSuffix *string
+		diagFormat   *string
+		targetOS     *string
+		targetArch   *string
+		stdinSource  *string
+		declOrder    *string
+		fieldOrder   *string
+		pyDataclass  *bool
+		maximumDepth *int
+		partialLoads *bool
+		queryExpress *string
+		selectMarked *bool
+		listMethods  *bool
+		reportImpls  *bool
+		showPosition *bool
 		includeTests *bool
+		disableCache *bool
 		includeUnexp *bool
 		printVersion *bool
+		stdout       io.Writer
+		stderr       io.Writer
 	}
 	flagArray []string
 )
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line arguments, reading the -stdin source
+// from stdin, writing the results to stdout and messages to stderr.
+// It returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
 	write := func(msg string) {
-		out := flag.CommandLine.Output()
-		_, _ = fmt.Fprintf(out, "typex: %s\n", msg)
+		_, _ = fmt.Fprintf(stderr, "typex: %s\n", msg)
 	}
 
-	opts, err := getOpts()
+	fs := flag.NewFlagSet("typex", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { usage(stderr) }
+
+	flags := newOpts(fs)
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		return 2 // flag reports its own errors
+	}
+	opts, err := checkOpts(fs, *flags)
 	if err != nil {
 		write(err.Error())
-		os.Exit(1)
+		return 2
 	}
-	if *opts.printVersion {
+	if *opts.printVersion && len(opts.pathPatterns) == 0 {
 		write(Version + " " + runtime.GOOS + " " + runtime.GOARCH)
-		os.Exit(0)
+		return 0
 	}
+	opts.stdout, opts.stderr = stdout, stderr
 
 	pac := typex.Packagist{
 		PathFilterFunc:    typex.CreatePathFilterFunc(opts.includeParts, opts.excludeParts),
+		StopFilterFunc:    typex.CreatePathFilterFunc(opts.stopPatterns, nil),
+		TypeFilterFunc:    opts.typeQuery,
 		IncludeUnexported: *opts.includeUnexp,
 		IncludeTestFiles:  *opts.includeTests,
+		MaxDepth:          *opts.maximumDepth,
+		SelectMarked:      *opts.selectMarked,
+		Partial:           *opts.partialLoads,
+		Contexts:          opts.contexts,
+		Env:               opts.environVars,
+	}
+	if dir, err := typex.DefaultCacheDir(); err == nil && !*opts.disableCache {
+		pac.Cache = &typex.Cache{Dir: dir}
+	}
+	if *opts.printVersion {
+		defer func() {
+			write(Version + " " + runtime.GOOS + " " + runtime.GOARCH)
+			if pac.Cache == nil {
+				write("cache disabled")
+				return
+			}
+			write("cache " + pac.Cache.Dir + ": " + pac.Cache.Stats.String())
+		}()
+	}
+	fail := func(err error) int {
+		if diags, ok := err.(typex.Diagnostics); ok {
+			report(opts, diags)
+		} else {
+			write(err.Error())
+		}
+		return 1
+	}
+	// done reports the diagnostics of a partial inspection.
+	done := func() int {
+		if diags := pac.Diagnostics(); len(diags) > 0 {
+			report(opts, diags)
+			return 3
+		}
+		return 0
+	}
+
+	if *opts.stdinSource != "" {
+		overlay, err := readOverlay(*opts.stdinSource, stdin)
+		if err != nil {
+			return fail(err)
+		}
+		pac.Overlay = overlay
+	}
+
+	if len(opts.reverseParts) > 0 {
+		if err = exportReverse(opts, &pac); err != nil {
+			return fail(err)
+		}
+		return done()
 	}
 	types, err := pac.Inspect(opts.pathPatterns...)
 	if err != nil {
-		write(err.Error())
-		os.Exit(1)
+		return fail(err)
+	}
+	for _, d := range pac.Divergences() {
+		if len(d.Differs) > 0 {
+			write(fmt.Sprintf("warning: %s differs in %s", d.Name, strings.Join(d.Differs, "; ")))
+		}
+		if len(d.Missing) > 0 {
+			write(fmt.Sprintf("warning: %s missing in %s", d.Name, strings.Join(d.Missing, "; ")))
+		}
+	}
+	dirs := pac.Directives()
+	types = dirs.Prune(*opts.outputLayout, types)
+	opts.pathReplace = dirs.RenameFunc(*opts.outputLayout, typex.CreatePathReplaceFunc(opts.replaceParts))
+	if policy := identifierPolicy(opts); policy != nil {
+		var renames []typex.Rename
+		opts.pathReplace, renames = policy.RenameFunc(types, opts.pathReplace)
+		for _, r := range renames {
+			write(fmt.Sprintf("warning: renamed %s to %s", r.From, r.To))
+		}
+	}
+	flat := flatNamespace(opts)
+	if c := typex.FindCollisions(types, opts.pathReplace, flat); len(c) > 0 {
+		if *opts.onCollision != "suffix" {
+			write(c.Error())
+			return 1
+		}
+		opts.pathReplace = typex.DisambiguateFunc(types, opts.pathReplace, flat)
+	}
+	if *opts.showPosition {
+		opts.typePosition = pac.Position
+	}
+	opts.typeDoc = pac.Doc
+	opts.ranking = typex.Rank(types, typex.Order(*opts.declOrder), pac.Source)
+
+	if *opts.reportImpls {
+		if err = exportImplementations(opts, &pac, types); err != nil {
+			return fail(err)
+		}
+		return done()
+	}
+
+	if err = export(opts, types); err != nil {
+		return fail(err)
 	}
+	return done()
+}
+
+// buildContexts returns the build configurations given by the
+// product of the -goos, -goarch and -tags lists. Several
+// configurations are inspected and merged.
+func buildContexts(opts options) []typex.BuildContext {
+	list := func(s string) []string {
+		if v := strings.Split(s, ","); s != "" {
+			return v
+		}
+		return []string{""}
+	}
+	tagSets := [][]string{nil}
+	if len(opts.buildTagSets) > 0 {
+		tagSets = tagSets[:0]
+		for _, tags := range opts.buildTagSets {
+			tagSets = append(tagSets, strings.FieldsFunc(tags, func(r rune) bool {
+				return r == ',' || r == ' '
+			}))
+		}
+	}
+
+	contexts := make([]typex.BuildContext, 0)
+	for _, goos := range list(*opts.targetOS) {
+		for _, goarch := range list(*opts.targetArch) {
+			for _, tags := range tagSets {
+				contexts = append(contexts, typex.BuildContext{GOOS: goos, GOARCH: goarch, Tags: tags})
+			}
+		}
+	}
+	return contexts
+}
 
+// readOverlay returns the overlay of the Go file with the source read from r.
+func readOverlay(file string, r io.Reader) (map[string][]byte, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{abs: src}, nil
+}
+
+// report writes the diagnostics of the inspected packages
+// to stderr, grouped by package or as JSON array.
+func report(opts options, diags typex.Diagnostics) {
+	if *opts.diagFormat == "json" {
+		enc := json.NewEncoder(opts.stderr)
+		enc.SetIndent("", "    ")
+		_ = enc.Encode(diags)
+		return
+	}
+	write(opts.stderr, "typex: %s\n", diags.Error())
+}
+
+// export writes the types in the layout of the options.
+func export(opts options, types typex.TypeMap) error {
 	switch *opts.outputLayout {
 	case "go":
-		err = exportGo(opts, types)
+		return exportGo(opts, types)
 	case "ts-type":
-		err = exportTs(opts, types, false)
+		return exportTs(opts, types, false)
 	case "ts-class":
-		err = exportTs(opts, types, true)
+		return exportTs(opts, types, true)
+	case "json":
+		return exportJSON(opts, types)
+	case "dot":
+		return exportDot(opts, types)
+	case "mermaid":
+		return exportUML(opts, types, uml.Mermaid, uml.NewMermaidLayout)
+	case "plantuml":
+		return exportUML(opts, types, uml.PlantUML, uml.NewPlantUMLLayout)
+	case "proto":
+		return exportProto(opts, types)
+	case "graphql":
+		return exportGraphQL(opts, types)
+	case "python":
+		return exportPython(opts, types)
+	case "rust":
+		return exportRust(opts, types)
+	case "kotlin":
+		return exportKotlin(opts, types)
+	case "swift":
+		return exportSwift(opts, types)
+	case "csharp":
+		return exportCSharp(opts, types)
+	case "java":
+		return exportJava(opts, types)
+	case "dart":
+		return exportDart(opts, types)
 	}
-	if err != nil {
-		write(err.Error())
-		os.Exit(1)
+	return nil
+}
+
+// flatNamespace reports the types declared by layouts with a single
+// namespace, nil for layouts declaring types in their packages.
+func flatNamespace(opts options) func(types.Type) bool {
+	if *opts.outputLayout == "graphql" {
+		return graphql.Declared
 	}
+	return nil
+}
+
+// identifierPolicy returns the naming policy of the layout, if any.
+// Protocol Buffers, GraphQL and Dart identifiers are limited to ASCII.
+func identifierPolicy(opts options) *typex.IdentifierPolicy {
+	policy := map[string]typex.IdentifierPolicy{
+		"ts-type":  {Reserved: ts.Reserved},
+		"ts-class": {Reserved: ts.Reserved},
+		"proto":    {Reserved: proto.Reserved, IsIdentRune: typex.IsASCIIIdent},
+		"python":   {Reserved: python.Reserved},
+		"rust":     {Reserved: rust.Reserved},
+		"kotlin":   {Reserved: kotlin.Reserved},
+		"swift":    {Reserved: swift.Reserved},
+		"csharp":   {Reserved: csharp.Reserved},
+		"java":     {Reserved: java.Reserved},
+		"dart":     {Reserved: dart.Reserved, IsIdentRune: typex.IsASCIIIdent},
+		"graphql": {
+			Reserved: func(s string) bool {
+				return graphql.Reserved(s) || s == *opts.customScalar
+			},
+			IsIdentRune: typex.IsASCIIIdent,
+		},
+	}
+
+	p, ok := policy[*opts.outputLayout]
+	if !ok {
+		return nil
+	}
+	p.Suffix = *opts.renameSuffix
+	return &p
 }
 
 func exportGo(opts options, types typex.TypeMap) error {
 	tr := g0.TypeRender{
-		PathReplaceFunc:   typex.CreatePathReplaceFunc(opts.replaceParts),
+		PathReplaceFunc:   opts.pathReplace,
+		PositionFunc:      opts.typePosition,
 		IncludeUnexported: *opts.includeUnexp,
+		IncludeMethods:    *opts.listMethods,
+		SortFields:        *opts.fieldOrder == "name",
 	}
 	tw := typex.TreeWalk{
-		Layout: g0.NewTreeLayout(os.Stdout),
+		Layout:  g0.NewTreeLayout(opts.stdout),
+		Ranking: opts.ranking.Rekey(tr.Path),
 	}
 	return tw.Walk(tr.Render(types))
 }
 
 func exportTs(opts options, types typex.TypeMap, exportObjs bool) error {
 	tr := ts.TypeRender{
-		PathReplaceFunc:   typex.CreatePathReplaceFunc(opts.replaceParts),
+		PathReplaceFunc:   opts.pathReplace,
+		PositionFunc:      opts.typePosition,
+		DocFunc:           opts.typeDoc,
 		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		SortFields:        *opts.fieldOrder == "name",
 	}
 	tw := typex.TreeWalk{
-		Layout: ts.NewModuleLayout(os.Stdout),
+		Layout:  ts.NewModuleLayout(opts.stdout),
+		Ranking: opts.ranking.Rekey(tr.Path),
 	}
 	return tw.Walk(tr.Render(types, exportObjs))
 }
 
-func getOpts() (options, error) {
+func exportJSON(opts options, types typex.TypeMap) error {
+	tr := dump.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		PositionFunc:      opts.typePosition,
+		IncludeUnexported: *opts.includeUnexp,
+		IncludeMethods:    *opts.listMethods,
+		Ranking:           opts.ranking,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	return dump.Write(opts.stdout, tr.Render(types))
+}
+
+func exportDot(opts options, types typex.TypeMap) error {
+	tr := dot.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+	}
+	tw := typex.TreeWalk{
+		Layout:  dot.NewGraphLayout(opts.stdout, tr.Edges(types, opts.ranking)),
+		Ranking: opts.ranking.Rekey(tr.Path),
+	}
+	return tw.Walk(tr.Render(types))
+}
+
+func exportUML(opts options, types typex.TypeMap, d uml.Dialect, l func(io.Writer, []string) typex.Layout) error {
+	tr := uml.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		Dialect:           d,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	tw := typex.TreeWalk{
+		Layout:  l(opts.stdout, tr.Relations(types, opts.ranking)),
+		Ranking: opts.ranking.Rekey(tr.Path),
+	}
+	return tw.Walk(tr.Render(types))
+}
+
+func exportProto(opts options, types typex.TypeMap) error {
+	lock, err := proto.ReadLock(*opts.lockFilePath)
+	if err != nil {
+		return err
+	}
+	tr := proto.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		Lock:              lock,
+		Ranking:           opts.ranking,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	if err = typex.WriteFiles(opts.stdout, *opts.outputFolder, "//", tr.Render(types)); err != nil {
+		return err
+	}
+	if *opts.lockFilePath != "" {
+		return lock.Write(*opts.lockFilePath)
+	}
+	return nil
+}
+
+func exportGraphQL(opts options, types typex.TypeMap) error {
+	tr := graphql.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		Scalar:            *opts.customScalar,
+		Ranking:           opts.ranking,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	if len(opts.inputParts) > 0 {
+		tr.InputFilterFunc = typex.CreatePathFilterFunc(opts.inputParts, nil)
+	}
+	schema, warnings := tr.Render(types)
+
+	for _, w := range warnings {
+		_, _ = fmt.Fprintf(opts.stderr, "typex: warning: %s\n", w)
+	}
+	_, err := fmt.Fprintln(opts.stdout, schema)
+	return err
+}
+
+func exportPython(opts options, types typex.TypeMap) error {
+	tr := python.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		Dataclasses:       *opts.pyDataclass,
+		Ranking:           opts.ranking,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	return typex.WriteFiles(opts.stdout, *opts.outputFolder, "#", tr.Render(types))
+}
+
+func exportRust(opts options, types typex.TypeMap) error {
+	tr := rust.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	tw := typex.TreeWalk{
+		Layout:  rust.NewModuleLayout(opts.stdout),
+		Ranking: opts.ranking.Rekey(tr.Path),
+	}
+	return tw.Walk(tr.Render(types))
+}
+
+func exportKotlin(opts options, types typex.TypeMap) error {
+	tr := kotlin.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		Ranking:           opts.ranking,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	return typex.WriteFiles(opts.stdout, *opts.outputFolder, "//", tr.Render(types))
+}
+
+func exportSwift(opts options, types typex.TypeMap) error {
+	tr := swift.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	tw := typex.TreeWalk{
+		Layout:  swift.NewNamespaceLayout(opts.stdout),
+		Ranking: opts.ranking.Rekey(tr.Path),
+	}
+	return tw.Walk(tr.Render(types))
+}
+
+func exportCSharp(opts options, types typex.TypeMap) error {
+	tr := csharp.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		Ranking:           opts.ranking,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	return typex.WriteFiles(opts.stdout, *opts.outputFolder, "//", tr.Render(types))
+}
+
+func exportJava(opts options, types typex.TypeMap) error {
+	tr := java.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		Ranking:           opts.ranking,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	return typex.WriteFiles(opts.stdout, *opts.outputFolder, "//", tr.Render(types))
+}
+
+func exportDart(opts options, types typex.TypeMap) error {
+	tr := dart.TypeRender{
+		PathReplaceFunc:   opts.pathReplace,
+		IncludeUnexported: *opts.includeUnexp,
+		TagKey:            *opts.serialTagKey,
+		Ranking:           opts.ranking,
+		SortFields:        *opts.fieldOrder == "name",
+	}
+	return typex.WriteFiles(opts.stdout, *opts.outputFolder, "//", tr.Render(types))
+}
+
+func exportImplementations(opts options, pac *typex.Packagist, types typex.TypeMap) error {
+	impls := pac.Implementations(types)
+
+	switch *opts.outputLayout {
+	case "go":
+		tr := g0.TypeRender{
+			PathReplaceFunc:   opts.pathReplace,
+			IncludeUnexported: *opts.includeUnexp,
+		}
+		tw := typex.TreeWalk{
+			Layout: g0.NewTreeLayout(opts.stdout),
+		}
+		return tw.Walk(tr.RenderImplementations(types, impls))
+	case "json":
+		tr := dump.TypeRender{
+			PathReplaceFunc:   opts.pathReplace,
+			IncludeUnexported: *opts.includeUnexp,
+		}
+		return dump.Write(opts.stdout, tr.RenderImplementations(types, impls))
+	}
+	return fmt.Errorf("layout %q is not available with -impl", *opts.outputLayout)
+}
+
+func exportReverse(opts options, pac *typex.Packagist) error {
+	idx, err := pac.Reverse(opts.pathPatterns...)
+	if err != nil {
+		return err
+	}
+	refs := idx.Query(typex.CreatePathFilterFunc(opts.reverseParts, nil))
+	opts.pathReplace = pac.Directives().RenameFunc(*opts.outputLayout, typex.CreatePathReplaceFunc(opts.replaceParts))
+
+	switch *opts.outputLayout {
+	case "go":
+		tr := g0.TypeRender{
+			PathReplaceFunc:   opts.pathReplace,
+			IncludeUnexported: *opts.includeUnexp,
+		}
+		tw := typex.TreeWalk{
+			Layout: g0.NewTreeLayout(opts.stdout),
+		}
+		return tw.Walk(tr.RenderReverse(refs))
+	case "json":
+		tr := dump.TypeRender{
+			PathReplaceFunc:   opts.pathReplace,
+			IncludeUnexported: *opts.includeUnexp,
+		}
+		return dump.Write(opts.stdout, tr.RenderReverse(refs))
+	}
+	return fmt.Errorf("layout %q is not available with -R", *opts.outputLayout)
+}
+
+// newOpts defines the flags of fs and returns the options they set.
+func newOpts(fs *flag.FlagSet) *options {
 	opts := options{
 		includeParts: flagArray{},
 		excludeParts: flagArray{},
 		replaceParts: flagArray{},
-		outputLayout: flag.String("l", "", ""),
-		includeTests: flag.Bool("t", false, ""),
-		includeUnexp: flag.Bool("u", false, ""),
-		printVersion: flag.Bool("v", false, ""),
+		outputLayout: fs.String("l", "", ""),
+		serialTagKey: fs.String("tag", typex.DefaultTagKey, ""),
+		outputFolder: fs.String("o", "", ""),
+		lockFilePath: fs.String("lock", "", ""),
+		customScalar: fs.String("scalar", graphql.DefaultScalar, ""),
+		onCollision:  fs.String("collisions", "fail", ""),
+		renameSuffix: fs.String("suffix", typex.DefaultSuffix, ""),
+		diagFormat:   fs.String("diag", "text", ""),
+		targetOS:     fs.String("goos", "", ""),
+		targetArch:   fs.String("goarch", "", ""),
+		stdinSource:  fs.String("stdin", "", ""),
+		declOrder:    fs.String("order", string(typex.OrderName), ""),
+		fieldOrder:   fs.String("fields", "source", ""),
+		pyDataclass:  fs.Bool("dataclasses", false, ""),
+		maximumDepth: fs.Int("depth", 0, ""),
+		partialLoads: fs.Bool("partial", false, ""),
+		queryExpress: fs.String("q", "", ""),
+		selectMarked: fs.Bool("m", false, ""),
+		listMethods:  fs.Bool("methods", false, ""),
+		reportImpls:  fs.Bool("impl", false, ""),
+		showPosition: fs.Bool("pos", false, ""),
+		includeTests: fs.Bool("t", false, ""),
+		disableCache: fs.Bool("no-cache", false, ""),
+		includeUnexp: fs.Bool("u", false, ""),
+		printVersion: fs.Bool("v", false, ""),
 	}
-	flag.Var(&opts.includeParts, "f", "")
-	flag.Var(&opts.excludeParts, "x", "")
-	flag.Var(&opts.replaceParts, "r", "")
-	flag.Usage = usage
-	flag.Parse()
+	fs.Var(&opts.includeParts, "f", "")
+	fs.Var(&opts.excludeParts, "x", "")
+	fs.Var(&opts.replaceParts, "r", "")
+	fs.Var(&opts.reverseParts, "R", "")
+	fs.Var(&opts.inputParts, "input", "")
+	fs.Var(&opts.stopPatterns, "stop", "")
+	fs.Var(&opts.buildTagSets, "tags", "")
+	fs.Var(&opts.environVars, "env", "")
+	return &opts
+}
 
+// checkOpts validates the options after fs has been parsed.
+func checkOpts(fs *flag.FlagSet, opts options) (options, error) {
 	switch *opts.outputLayout {
-	case "go", "ts-type", "ts-class":
-	default:
+	case "go", "ts-type", "ts-class", "json", "dot", "mermaid", "plantuml", "proto",
+		"graphql", "python", "rust", "kotlin", "swift", "csharp", "java", "dart":
+	case "":
 		*opts.outputLayout = "go"
+	default:
+		return opts, fmt.Errorf("invalid layout %q", *opts.outputLayout)
+	}
+	switch *opts.outputLayout {
+	case "go", "json":
+	default:
+		if *opts.reportImpls {
+			return opts, fmt.Errorf("layout %q is not available with -impl", *opts.outputLayout)
+		}
+		if len(opts.reverseParts) > 0 {
+			return opts, fmt.Errorf("layout %q is not available with -R", *opts.outputLayout)
+		}
 	}
 
-	opts.pathPatterns = flag.Args()
+	switch *opts.onCollision {
+	case "fail", "suffix":
+	default:
+		return opts, fmt.Errorf("invalid collision strategy %q", *opts.onCollision)
+	}
+	switch typex.Order(*opts.declOrder) {
+	case typex.OrderName, typex.OrderSource, typex.OrderDeps:
+	default:
+		return opts, fmt.Errorf("invalid order %q", *opts.declOrder)
+	}
+	switch *opts.fieldOrder {
+	case "source", "name":
+	default:
+		return opts, fmt.Errorf("invalid field order %q", *opts.fieldOrder)
+	}
+	switch *opts.diagFormat {
+	case "text", "json":
+	default:
+		return opts, fmt.Errorf("invalid diagnostics format %q", *opts.diagFormat)
+	}
+	if s := "x" + *opts.renameSuffix; s == "x" || (typex.IdentifierPolicy{IsIdentRune: typex.IsASCIIIdent}).Identifier(s) != s {
+		return opts, fmt.Errorf("invalid suffix %q", *opts.renameSuffix)
+	}
+	for _, v := range opts.environVars {
+		if i := strings.Index(v, "="); i < 1 {
+			return opts, fmt.Errorf("invalid env %q", v)
+		}
+	}
+	opts.contexts = buildContexts(opts)
+	if len(opts.contexts) > 1 && (*opts.reportImpls || len(opts.reverseParts) > 0) {
+		return opts, fmt.Errorf("-impl and -R support a single build configuration")
+	}
+	if *opts.maximumDepth < 0 {
+		return opts, fmt.Errorf("invalid depth %d", *opts.maximumDepth)
+	}
+	if *opts.queryExpress != "" {
+		query, err := typex.ParseQuery(*opts.queryExpress)
+		if err != nil {
+			return opts, err
+		}
+		opts.typeQuery = query
+	}
+
+	opts.pathPatterns = fs.Args()
+	if s := *opts.stdinSource; s != "" {
+		if !strings.HasSuffix(s, ".go") {
+			return opts, fmt.Errorf("invalid stdin file %q", s)
+		}
+		if len(opts.pathPatterns) == 0 {
+			opts.pathPatterns = []string{s}
+		}
+	}
 	if len(opts.includeParts) == 0 {
 		opts.includeParts = []string{".*"}
 	}
@@ -134,13 +732,44 @@ func write(w io.Writer, f string, a ...interface{}) {
 	_, _ = fmt.Fprintf(w, f, a...)
 }
 
-func usage() {
-	write(flag.CommandLine.Output(), `
+func usage(w io.Writer) {
+	write(w, `
 Usage: typex [options] package...
 Examine Go types and their transitive dependencies. Export
 results as TypeScript value objects (or types) declaration.
 
 Options:
+    -collisions <strategy>
+        Handling of distinct Go types mapped onto the same
+        name by the -r path replacement or by directives,
+        or of the same name in distinct packages in the
+        "graphql" layout declaring all types in a single
+        namespace:
+          * "fail":    report the colliding types and exit,
+                       the default
+          * "suffix":  keep the name of the first type in
+                       order of the Go names, append "_2",
+                       "_3", ... to the names of the others
+
+    -dataclasses
+        Render standard library dataclasses instead of
+        pydantic models in the "python" layout.
+
+    -depth <n>
+        Stop collecting transitive dependencies after <n>
+        hops from the filtered types. References beyond the
+        limit are rendered opaque, i.e. as bare type names
+        in the Go tree and as "unknown" in TypeScript.
+
+    -diag <format>
+        Format of the package load errors written to stderr,
+        "text" grouped by package, the default, or "json".
+
+    -env <key>=<value>
+        Environment variable of the go command loading the
+        packages, e.g. CGO_ENABLED=0. Repeating the -env
+        option is allowed.
+
     -f <name>
         Type name filter expression. Repeating the -f option
         is allowed, all expressions aggregate to an OR query.
@@ -154,11 +783,71 @@ Options:
         The result tree will contain additional references to
         transitive dependencies vital for the filtered types.
 
+    -fields <order>
+        Order of the struct fields in the output:
+          * "source":  order of declaration, the default
+          * "name":    alphabetical order of the Go names
+
+    -goarch <list>
+        Comma separated list of target architectures the
+        packages are inspected for, e.g. "amd64,arm64".
+        See -goos for lists of several values.
+
+    -goos <list>
+        Comma separated list of target operating systems the
+        packages are inspected for, e.g. "linux,windows".
+        Several values of -goos, -goarch or -tags inspect
+        every combination of them and merge the types, a
+        warning is printed for each type declared
+        differently or missing in some combinations. Not
+        applicable to -impl and -R.
+
+    -impl
+        Interface implementation report. For each filtered
+        interface list the concrete types implementing it,
+        for each filtered concrete type list the interfaces
+        it implements. Candidates are taken from the types
+        of the inspected packages and the filtered types.
+        Available with the "go" and "json" layout.
+
+    -input <name>
+        Render the types matching the <name> expression as
+        GraphQL input types, along with the structs they
+        refer to, which get an "Input" name suffix. Repeating
+        the -input option is allowed, all expressions
+        aggregate to an OR query.
+
     -l <layout>
         Modify the export layout. Available layouts are:
           * "go":       the default Go type dependency tree
           * "ts-type":  TypeScript type declaration projection
           * "ts-class": TypeScript value object projection
+          * "json":     JSON dump of the type declarations
+          * "dot":      Graphviz DOT type dependency graph
+          * "mermaid":  Mermaid class diagram
+          * "plantuml": PlantUML class diagram
+          * "proto":    Protocol Buffers schema, one file per
+                        package
+          * "graphql":  GraphQL schema definition language
+          * "python":   Python pydantic v2 models, one module
+                        per package
+          * "rust":     Rust serde structs in nested modules
+          * "kotlin":   Kotlin kotlinx.serialization data
+                        classes, one file per package
+          * "swift":    Swift Codable structs in nested
+                        namespaces
+          * "csharp":   C# records for System.Text.Json, one
+                        file per namespace
+          * "java":     Java records for Jackson, one file
+                        per declaration
+          * "dart":     Dart json_serializable classes, one
+                        library per package
+
+    -lock <file>
+        Field number lock file of the "proto" layout. Numbers
+        assigned to message fields are kept in <file>, so
+        regeneration does not renumber fields. Numbers of
+        removed fields are reserved and never reassigned.
 
     -r <old-path>:<new-path>
         Replace matching portions of <old-path> in a fully
@@ -171,9 +860,133 @@ Options:
         "github.com" reference from qualified type name path
         by omitting the <new-path> part after the colon. 
 
+    -m  Select the types marked with a directive comment
+        "//typex:export" in their declaration doc comment
+        instead of all types available for a filter.
+        Directives support per-type overrides, which can be
+        restricted to a layout, e.g. "go", "ts", "ts-class":
+          //typex:export                 select the type
+          //typex:export ts name=DTO     rename type in ts-*
+          //typex:skip json              omit type in json
+        Overrides apply regardless of the -m option.
+
+    -methods
+        List the methods declared on named types below their
+        declaration in the "go" and "json" layouts. Methods
+        with pointer receivers are marked with an asterisk.
+
+    -no-cache
+        Do not use the cache of inspected packages. The types
+        of the packages are cached in the directory named by
+        the TYPEXCACHE environment variable, or below the
+        user cache directory, keyed by the content of their
+        files and of the files of their dependencies.
+        Inspections with -q, -t or -u do not use the cache.
+
+    -o <dir>
+        Write the files of layouts producing one file per
+        package or declaration, i.e. "proto", "python",
+        "kotlin", "csharp", "java" and "dart", to the
+        directory <dir>. The files are written to stdout by
+        default, each one preceded by a comment line naming
+        the file.
+
+    -order <order>
+        Order of the declarations in the output. Packages and
+        files follow the first of their declarations:
+          * "name":    alphabetical order, the default
+          * "source":  order of the source positions
+          * "deps":    topological order, declarations follow
+                       the types they refer to
+
+    -partial
+        Continue with the packages loaded without errors,
+        instead of failing when any package is broken, e.g.
+        by a test file not compiling or a missing cgo header.
+        The errors are reported by -diag, the exit status
+        is 3 when the output misses the broken packages.
+
+    -pos
+        Annotate each declaration with its source position
+        (file:line relative to the module root), rendered as
+        trailing comment in the "go" layout, as @see tag in
+        TypeScript layouts and as "position" in JSON output.
+
+    -q <query>
+        Select types by a query expression. The expression
+        consists of key:value predicates, which can be
+        combined with AND, OR, NOT and parentheses. Adjacent
+        predicates aggregate to an AND query. Predicates:
+          * kind:<kind>        struct, interface, map, ...
+          * name:<name>        type name expression
+          * pkg:<path>         package path expression
+          * has-field:<name>   struct has the named field
+          * tag:<key>          struct has a field tag <key>
+          * implements:<name>  type implements interface
+          * doc:<text>         doc comment expression
+        The query applies in addition to -f and -x filters.
+
+    -R <name>
+        Reverse dependency query. Show the chains of types
+        and fields referring to the types matching <name>,
+        up to the root types not referred to by any other
+        type in the inspected packages. Methods declared
+        on non-interface types are not considered. A type
+        referring on several chains is expanded once, then
+        marked as repeated. Repeating the -R option is
+        allowed, all expressions aggregate to an OR query.
+        Available with the "go" and "json" layout.
+
+    -scalar <name>
+        Custom GraphQL scalar replacing types without a GraphQL
+        counterpart, i.e. maps, channels, functions and
+        interfaces, default: "JSON". A warning is printed for
+        each replaced field type.
+
+    -stop <path>
+        Stop collecting transitive dependencies at package
+        boundaries. Types declared in packages with import
+        paths matching <path> are rendered opaque, unless
+        they are matched by a filter expression. Repeating
+        the -stop option is allowed, all expressions
+        aggregate to an OR query.
+
+    -stdin <file>
+        Read the source of the Go file <file> from stdin, in
+        place of the file content on disk, if any. The file
+        is inspected when no package argument is given, e.g.
+        for the unsaved buffer of an editor.
+
+    -suffix <suffix>
+        Suffix appended to type names and package path
+        segments which are keywords or clash with built-in
+        names of the target language, default: "_". Other
+        invalid characters become underscores, e.g. letters
+        outside of ASCII in the "proto", "graphql" and "dart"
+        layouts. Each rename is reported as warning. Not
+        applicable to the "go", "json", "dot", "mermaid" and
+        "plantuml" layouts.
+
     -t  Go tests (files suffixed _test.go) will be included
         in the result tree available for a filter expression
 
+    -tag <key>
+        Struct tag key driving field renaming, omission and
+        optionality, default: "json". Options follow the
+        semantics of the library bound to the key: json,
+        yaml, xml, bson, msgpack, toml and mapstructure are
+        known, e.g. yaml "inline", bson "inline" or
+        mapstructure "squash" flatten a struct field. Any
+        other key is treated like "json". Not applicable to
+        the "go", "json", "dot", "mermaid" and "plantuml"
+        layouts.
+
+    -tags <list>
+        Comma separated list of build tags considered while
+        loading the packages, e.g. "integration,purego".
+        Repeating the -tags option inspects each list of
+        tags, see -goos.
+
     -u  Unexported types (lowercase names) will be included
         in the result tree available for a filter expression.
 
@@ -185,19 +998,47 @@ Options:
 
 More options:
     -h  Display this usage help and exit.
-    -v  Print program version and exit.
+    -v  Print program version and exit. Along with package
+        arguments, print the version and cache statistics
+        after the inspection.
 
 The 'package' argument denotes one or more package import path
 patterns to be inspected. Patterns must be separated by space.
 A pattern containing '...' specifies the active modules whose
 modules paths match the pattern.
 
+Arguments naming Go files, or directories outside of a module,
+are inspected without the go command, one package per directory.
+The package name is used as package path, imported packages are
+resolved in the directory of the files.
+
+The exit status is 0 on success, 1 on errors, 2 on malformed
+options and 3 on output partial due to package load errors.
+
 Examples:
     $ typex -u go/...
     $ typex -u -f=URL net/url
+    $ typex -methods -f=Duration time
+    $ typex -impl -f=io.Reader io bufio strings
     $ typex github.com/your/repository/...
     $ typex -l=ts-type github.com/your/repository/...
+    $ typex -l=ts-type -tag=yaml github.com/your/repository/...
     $ typex -r=github.com:a/b/c github.com/your/repository/...
+    $ typex -r=/v1: -r=/v2: -collisions=suffix github.com/your/repository/...
+    $ typex -l=kotlin -order=deps -fields=name github.com/your/repository/...
+    $ typex -l=dot github.com/your/repository/... | dot -Tsvg
+    $ typex -l=plantuml github.com/your/repository/... > types.puml
+    $ typex -l=graphql -input=Request github.com/your/repository/...
+    $ typex -l=proto -o=api -lock=api/proto.lock github.com/your/repository/...
+    $ typex -R=money.Amount github.com/your/repository/...
+    $ typex -q="kind:struct AND NOT has-field:ID" net/url
+    $ typex -m -l=ts-class github.com/your/repository/...
+    $ typex -q="implements:io.Reader OR doc:@api" github.com/your/repository/...
+    $ typex -depth=2 -stop=github.com/aws github.com/your/repository/...
+    $ typex -goos=linux,windows -tags=purego github.com/your/repository/...
+    $ typex -l=ts-type ./schemas/*.go
+    $ cat snippet.go | typex -stdin=snippet.go
+    $ typex -partial -diag=json github.com/your/repository/...
 
 This tool relies heavily on Go's package managing subsystem and
 is bound to its features and environmental execution context.
@@ -0,0 +1,328 @@
+// MIT license · Daniel T. Gorski · dtg [at] lengo [dot] org · 10/2026
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// go test -run TestRun_Golden -update
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+var packages = []string{
+	"./internal/testdata/p1",
+	"./internal/testdata/p2",
+	"./internal/testdata/p2/p3",
+	"./internal/testdata/p4",
+	"./internal/testdata/p5",
+	"./internal/testdata/p6",
+}
+
+var layouts = []string{
+	"go", "ts-type", "ts-class", "json", "dot", "mermaid", "plantuml", "proto",
+	"graphql", "python", "rust", "kotlin", "swift", "csharp", "java", "dart",
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	dir, err := os.MkdirTemp("", "typex")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("TYPEXCACHE", dir)
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// unreplaced are the layouts splitting qualified names into package
+// paths, rendered without path replacement in addition.
+var unreplaced = []string{"go", "dot", "plantuml"}
+
+// TestRun_Golden runs every layout against the testdata packages and
+// compares the output to the golden files, changes of any renderer
+// show up in the diff of the golden files.
+func TestRun_Golden(t *testing.T) {
+	runs := make(map[string][]string)
+	for _, layout := range layouts {
+		runs[layout] = args(layout)
+	}
+	for _, layout := range unreplaced {
+		runs[layout+"-unreplaced"] = []string{"-l=" + layout, "./internal/testdata/p6"}
+	}
+	names := make([]string, 0, len(runs))
+	for name := range runs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			golden := filepath.Join("internal", "testdata", "golden", name+".golden")
+			out := output(t, runs[name])
+
+			if *update {
+				if err := os.WriteFile(golden, out, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out, want) {
+				t.Errorf("output differs from %s, run with -update after review\n%s", golden, diff(t, golden, out))
+			}
+		})
+	}
+}
+
+// TestRun_TypeScript compiles the golden TypeScript files,
+// the test is skipped when tsc is not installed.
+func TestRun_TypeScript(t *testing.T) {
+	tsc, err := exec.LookPath("tsc")
+	if err != nil {
+		t.Skip("tsc not found")
+	}
+	for _, layout := range []string{"ts-type", "ts-class"} {
+		t.Run(layout, func(t *testing.T) {
+			src := filepath.Join(t.TempDir(), "types.ts")
+			if err := os.WriteFile(src, render(t, layout), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cmd := exec.Command(tsc, "--noEmit", "--strict", "--target", "es2020", src)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("%v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestRun_Partial(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		code int
+		out  bool
+		errs string
+	}{
+		{[]string{"./internal/testdata/p5", "./internal/testdata/p7"}, 1, false,
+			"typex: 1 error in 1 package\n" +
+				"    github.com/dtgorski/typex/internal/testdata/p7\n" +
+				"        internal/testdata/p7/types.go:8:11: type: undefined: Undefined\n"},
+		{[]string{"-partial", "./internal/testdata/p5", "./internal/testdata/p7"}, 3, true,
+			"typex: 1 error in 1 package\n" +
+				"    github.com/dtgorski/typex/internal/testdata/p7\n" +
+				"        internal/testdata/p7/types.go:8:11: type: undefined: Undefined\n"},
+		{[]string{"-partial", "-diag=json", "./internal/testdata/p7"}, 3, false,
+			`[
+    {
+        "package": "github.com/dtgorski/typex/internal/testdata/p7",
+        "position": "internal/testdata/p7/types.go:8:11",
+        "kind": "type",
+        "message": "undefined: Undefined"
+    }
+]
+`},
+		{[]string{"-partial", "./internal/testdata/p5"}, 0, true, ""},
+	} {
+		out, errs := &bytes.Buffer{}, &bytes.Buffer{}
+		if code := run(tc.args, nil, out, errs); code != tc.code {
+			t.Errorf("unexpected exit code %d\n%s", code, errs)
+		}
+		if (out.Len() > 0) != tc.out {
+			t.Errorf("unexpected output %q", out)
+		}
+		if errs.String() != tc.errs {
+			t.Errorf("unexpected %q", errs)
+		}
+	}
+}
+
+func TestRun_BuildContexts(t *testing.T) {
+	const p8 = "github.com/dtgorski/typex/internal/testdata/p8"
+
+	for _, tc := range []struct {
+		args []string
+		out  []string
+		errs string
+	}{
+		{[]string{"-goos=linux"}, []string{"Handle int32"}, ""},
+		{[]string{"-goos=windows", "-env=GOARCH=amd64"}, []string{"Handle uintptr"}, ""},
+		{[]string{"-tags=extra"}, []string{"Extra struct"}, ""},
+		{[]string{"-goos=linux,windows", "-tags=extra", "-tags="}, []string{"Extra struct", "Handle int32"},
+			"typex: warning: " + p8 + ".Extra missing in GOOS=linux; GOOS=windows\n" +
+				"typex: warning: " + p8 + ".Handle differs in GOOS=windows tags=extra; GOOS=windows\n"},
+	} {
+		args := append(tc.args, "./internal/testdata/p8")
+		out, errs := &bytes.Buffer{}, &bytes.Buffer{}
+
+		if code := run(args, nil, out, errs); code != 0 {
+			t.Errorf("unexpected exit code %d\n%s", code, errs)
+		}
+		for _, s := range tc.out {
+			if !strings.Contains(out.String(), s) {
+				t.Errorf("%v: missing %q in\n%s", tc.args, s, out)
+			}
+		}
+		if errs.String() != tc.errs {
+			t.Errorf("unexpected %q", errs)
+		}
+	}
+	errs := &bytes.Buffer{}
+	if code := run([]string{"-env=X", "."}, nil, &bytes.Buffer{}, errs); code != 2 {
+		t.Errorf("unexpected exit code %d", code)
+	}
+	if code := run([]string{"-impl", "-goos=linux,windows", "."}, nil, &bytes.Buffer{}, errs); code != 2 {
+		t.Errorf("unexpected exit code %d", code)
+	}
+}
+
+func TestRun_ExitCode(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		code int
+		errs string
+	}{
+		{[]string{"-h"}, 0, "Usage"},
+		{[]string{"-unknown"}, 2, "flag provided but not defined"},
+		{[]string{"-l=cobol", "."}, 2, `typex: invalid layout "cobol"`},
+		{[]string{"-l=ts-type", "-impl", "."}, 2, `typex: layout "ts-type" is not available with -impl`},
+		{[]string{"-l=dot", "-R=T", "."}, 2, `typex: layout "dot" is not available with -R`},
+		{[]string{"-order=random", "."}, 2, `typex: invalid order "random"`},
+		{[]string{"-q=kind:", "."}, 2, `typex: query: invalid predicate "kind:"`},
+		{[]string{"-q=kind:strcut", "."}, 2, `typex: query: invalid kind "strcut"`},
+		{[]string{"./internal/testdata/nonexistent"}, 1, "typex: "},
+		{[]string{"-l=graphql", "./internal/testdata/p1"}, 1, "typex: name collision of distinct types\n    T <- "},
+	} {
+		errs := &bytes.Buffer{}
+		if code := run(tc.args, nil, &bytes.Buffer{}, errs); code != tc.code {
+			t.Errorf("%v: unexpected exit code %d", tc.args, code)
+		}
+		if strings.Count(errs.String(), tc.errs) != 1 {
+			t.Errorf("%v: unexpected %q", tc.args, errs)
+		}
+	}
+}
+
+func TestRun_Sources(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"a.go":      "package snip\n\nimport \"time\"\n\ntype A struct {\n\tT time.Time\n\tB B\n}\n",
+		"b.go":      "package snip\n\ntype B int\n",
+		"c.go":      "//go:build ignore\n\npackage snip\n\ntype C int\n",
+		"a_test.go": "package snip\n\ntype D int\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, c := filepath.Join(dir, "a.go"), filepath.Join(dir, "c.go")
+
+	for _, tc := range []struct {
+		args  []string
+		stdin string
+		out   string
+	}{
+		{[]string{dir}, "", "├── snip\n│   ├── A struct {\n│   │       T time.Time\n│   │       B snip.B\n│   │   }\n│   └── B int\n"},
+		{[]string{c}, "", "└── snip\n    └── C int\n"},
+		{[]string{"-stdin=" + a}, "package snip\n\ntype E uint\n", "└── snip\n    └── E uint\n"},
+		{[]string{"-stdin=" + a, dir}, "package snip\n\ntype E uint\n", "└── snip\n    ├── B int\n    └── E uint\n"},
+	} {
+		out, errs := &bytes.Buffer{}, &bytes.Buffer{}
+		if code := run(tc.args, strings.NewReader(tc.stdin), out, errs); code != 0 {
+			t.Errorf("unexpected exit code %d\n%s", code, errs)
+		}
+		if !strings.HasPrefix(out.String(), tc.out) {
+			t.Errorf("%v: unexpected\n%s", tc.args, out)
+		}
+	}
+}
+
+func TestRun_Cache(t *testing.T) {
+	t.Setenv("TYPEXCACHE", t.TempDir())
+	render := func(args ...string) (string, string) {
+		args = append(args, "-v", "-pos", "-l=ts-type", "./internal/testdata/p5")
+		out, errs := &bytes.Buffer{}, &bytes.Buffer{}
+
+		if code := run(args, nil, out, errs); code != 0 {
+			t.Fatalf("unexpected exit code %d\n%s", code, errs)
+		}
+		return out.String(), errs.String()
+	}
+
+	want, _ := render("-no-cache")
+	for _, stats := range []string{": 0 hits, 1 miss, 1 store\n", ": 1 hit, 0 misses, 0 stores\n"} {
+		out, errs := render()
+		if !strings.HasSuffix(errs, stats) {
+			t.Errorf("unexpected %q", errs)
+		}
+		if out != want {
+			t.Errorf("unexpected\n%s", out)
+		}
+	}
+	if _, errs := render("-q=kind:struct"); !strings.HasSuffix(errs, ": 0 hits, 0 misses, 0 stores\n") {
+		t.Errorf("unexpected %q", errs)
+	}
+
+	// Export data omits unexported types, -u bypasses the cache.
+	unexported := func() (string, string) {
+		args := []string{"-v", "-u", "./internal/testdata/p6"}
+		out, errs := &bytes.Buffer{}, &bytes.Buffer{}
+		if code := run(args, nil, out, errs); code != 0 {
+			t.Fatalf("unexpected exit code %d\n%s", code, errs)
+		}
+		return out.String(), errs.String()
+	}
+	first, _ := unexported()
+	second, errs := unexported()
+	if first != second || !strings.Contains(first, "hidden struct") {
+		t.Errorf("unexpected\n%s\n%s", first, second)
+	}
+	if !strings.HasSuffix(errs, ": 0 hits, 0 misses, 0 stores\n") {
+		t.Errorf("unexpected %q", errs)
+	}
+	if _, errs := render("-no-cache"); !strings.HasSuffix(errs, "typex: cache disabled\n") {
+		t.Errorf("unexpected %q", errs)
+	}
+}
+
+func render(t *testing.T, layout string) []byte {
+	return output(t, args(layout))
+}
+
+// args returns the arguments of a golden run of the layout. GraphQL
+// declares the equally named types of the packages in one namespace.
+func args(layout string) []string {
+	args := []string{"-l=" + layout, "-r=.*/testdata:"}
+	if layout == "graphql" {
+		args = append(args, "-collisions=suffix")
+	}
+	return append(args, packages...)
+}
+
+func output(t *testing.T, args []string) []byte {
+	out, errs := &bytes.Buffer{}, &bytes.Buffer{}
+
+	if code := run(args, nil, out, errs); code != 0 {
+		t.Fatalf("exit code %d\n%s", code, errs)
+	}
+	return out.Bytes()
+}
+
+func diff(t *testing.T, golden string, out []byte) string {
+	tmp := filepath.Join(t.TempDir(), filepath.Base(golden))
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// diff exits with 1 when the files differ
+	b, _ := exec.Command("diff", "-u", golden, tmp).Output()
+	return string(b)
+}